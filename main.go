@@ -3,17 +3,144 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 
+	"go-mcp/mcp/config"
 	"go-mcp/mcp/router"
+	"go-mcp/mcp/tools"
 )
 
 func main() {
-	server := router.NewServer()
+	configPath := flag.String("config", os.Getenv(config.EnvName("config")),
+		"服务器配置文件路径，支持 .toml、.yaml 与 .yml（环境变量 "+config.EnvName("config")+"）")
+	applyFlags := config.BindFlags(flag.CommandLine)
+	flag.Parse()
 
-	if err := server.Run(); err != nil && !errors.Is(err, context.Canceled) {
+	// 优先级从低到高：默认值、配置文件、环境变量、命令行参数
+	cfg := config.Default()
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+	}
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	if err := applyFlags(&cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+
+	registry := tools.NewRegistry()
+	if err := tools.RegisterBuiltins(registry); err != nil {
+		fmt.Fprintf(os.Stderr, "注册工具失败: %v\n", err)
+		os.Exit(1)
+	}
+	if err := cfg.Validate(registry); err != nil {
+		fmt.Fprintf(os.Stderr, "配置无效:\n%v\n", err)
+		os.Exit(2)
+	}
+
+	// 默认参数按全部工具计算，之后再停用未启用的工具
+	toolDefaults := cfg.ToolDefaults(registry)
+	if len(cfg.Tools.Enabled) > 0 {
+		for _, name := range registry.Names() {
+			if !slices.Contains(cfg.Tools.Enabled, name) {
+				registry.Disable(name)
+			}
+		}
+	}
+	for _, name := range cfg.Tools.Disabled {
+		registry.Disable(name)
+	}
+
+	logger, closeLog, err := newLogger(cfg.Log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	defer closeLog()
+
+	server := router.NewServer(
+		router.WithRegistry(registry),
+		router.WithLogger(logger),
+		router.WithMaxConcurrency(cfg.MaxConcurrency),
+		router.WithMaxMessageSize(cfg.MaxMessageSize),
+		router.WithKeepAlive(cfg.KeepAlive.Interval, cfg.KeepAlive.Timeout),
+		router.WithAllowedHosts(cfg.AllowedHosts...),
+		router.WithSessionIdleTimeout(cfg.SessionIdleTimeout),
+		router.WithLocale(cfg.Locale()),
+		router.WithToolTimeout(cfg.Tools.Timeout),
+		router.WithToolTimeouts(cfg.ToolTimeouts()),
+		router.WithToolDefaults(toolDefaults),
+		router.WithSubscriptionOptions(router.SubscriptionOptions{
+			Interval:    cfg.Subscriptions.Interval,
+			MinDelta:    cfg.Subscriptions.MinDelta,
+			MaxInterval: cfg.Subscriptions.MaxInterval,
+		}),
+	)
+
+	// 传输方式已在 Validate 中校验
+	var t router.Transport
+	switch cfg.Transport {
+	case "stdio":
+		t = router.StdioTransport()
+	case "http":
+		t = router.HTTPTransport(cfg.Addr, cfg.Endpoint)
+	case "sse":
+		t = router.SSETransport(cfg.Addr)
+	case "ws":
+		t = router.WebSocketTransport(cfg.Addr, cfg.Endpoint)
+	}
+
+	// 收到中断或终止信号时取消 ctx，服务器随之退出
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = server.Serve(ctx, t)
+	if err != nil && !errors.Is(err, context.Canceled) {
 		fmt.Fprintf(os.Stderr, "服务器启动失败: %v\n", err)
+		closeLog()
 		os.Exit(1)
 	}
 }
+
+// newLogger 创建服务器日志记录器；日志不能写入标准输出，否则会破坏 stdio 传输的 JSON-RPC 消息流。
+// 返回的函数用于关闭日志文件。
+func newLogger(cfg config.LogConfig) (*slog.Logger, func(), error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, nil, fmt.Errorf("不支持的日志级别: %s", cfg.Level)
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	out := os.Stderr
+	closeLog := func() {}
+	if cfg.File != "" {
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("打开日志文件失败: %v", err)
+		}
+		out = file
+		closeLog = func() { file.Close() }
+	}
+
+	switch cfg.Format {
+	case "text":
+		return slog.New(slog.NewTextHandler(out, opts)), closeLog, nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(out, opts)), closeLog, nil
+	default:
+		closeLog()
+		return nil, nil, fmt.Errorf("不支持的日志格式: %s", cfg.Format)
+	}
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
+	"go-mcp/mcp/router"
+	"go-mcp/mcp/tools"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config 为服务器配置，可以从 TOML 或 YAML 文件加载，再由环境变量与命令行参数覆盖。
+// 优先级从低到高依次为：默认值、配置文件、环境变量、命令行参数。
+type Config struct {
+	// Transport 为传输方式: stdio、http、sse（旧版 HTTP+SSE）或 ws
+	Transport string `toml:"transport" yaml:"transport"`
+	// Addr 为 HTTP/SSE/WebSocket 传输的监听地址
+	Addr string `toml:"addr" yaml:"addr"`
+	// Endpoint 为 HTTP/WebSocket 传输的 MCP 端点路径
+	Endpoint string `toml:"endpoint" yaml:"endpoint"`
+	// AllowedHosts 为本机地址之外允许访问 HTTP/SSE/WebSocket 传输的主机名，用于防御 DNS 重绑定攻击；
+	// 监听非本机地址并通过域名或局域网 IP 访问时需要列出
+	AllowedHosts []string `toml:"allowed_hosts" yaml:"allowed_hosts"`
+	// SessionIdleTimeout 为 Streamable HTTP 会话空闲多久后自动结束，为 0 时不过期
+	SessionIdleTimeout time.Duration `toml:"session_idle_timeout" yaml:"session_idle_timeout"`
+
+	// MaxConcurrency 为同时处理的请求数上限
+	MaxConcurrency int `toml:"max_concurrency" yaml:"max_concurrency"`
+	// MaxMessageSize 为单条 JSON-RPC 消息的字节数上限
+	MaxMessageSize int64 `toml:"max_message_size" yaml:"max_message_size"`
+
+	KeepAlive     KeepAliveConfig     `toml:"keepalive" yaml:"keepalive"`
+	Output        OutputConfig        `toml:"output" yaml:"output"`
+	Log           LogConfig           `toml:"log" yaml:"log"`
+	Subscriptions SubscriptionsConfig `toml:"subscriptions" yaml:"subscriptions"`
+	Tools         ToolsConfig         `toml:"tools" yaml:"tools"`
+
+	// Tool 按工具名称配置执行时限与默认参数
+	Tool map[string]ToolConfig `toml:"tool" yaml:"tool"`
+}
+
+// KeepAliveConfig 控制服务器主动发起的 ping。
+type KeepAliveConfig struct {
+	// Interval 为 ping 的周期，为 0 时不发送
+	Interval time.Duration `toml:"interval" yaml:"interval"`
+	// Timeout 为等待客户端应答的时长，超时即关闭会话
+	Timeout time.Duration `toml:"timeout" yaml:"timeout"`
+}
+
+// OutputConfig 控制工具描述与输出的默认语言和格式，客户端可以在会话或单次调用中覆盖。
+type OutputConfig struct {
+	Locale string `toml:"locale" yaml:"locale"`
+	// Format 为工具未指定 format 参数时的输出格式: text、markdown、json 或 csv
+	Format string `toml:"format" yaml:"format"`
+}
+
+// LogConfig 控制服务器日志。日志不能写入标准输出，否则会破坏 stdio 传输的 JSON-RPC 消息流。
+type LogConfig struct {
+	// Level 为日志级别: debug、info、warn 或 error
+	Level string `toml:"level" yaml:"level"`
+	// Format 为日志格式: text 或 json
+	Format string `toml:"format" yaml:"format"`
+	// File 为日志文件路径，为空时写入标准错误
+	File string `toml:"file" yaml:"file"`
+}
+
+// SubscriptionsConfig 控制资源订阅的后台采样周期与推送阈值。
+type SubscriptionsConfig struct {
+	Interval    time.Duration `toml:"interval" yaml:"interval"`
+	MinDelta    float64       `toml:"min_delta" yaml:"min_delta"`
+	MaxInterval time.Duration `toml:"max_interval" yaml:"max_interval"`
+}
+
+// ToolsConfig 控制启用的工具及默认执行时限。
+type ToolsConfig struct {
+	// Enabled 为启用的工具，为空时启用全部内置工具
+	Enabled []string `toml:"enabled" yaml:"enabled"`
+	// Disabled 为停用的工具，优先于 Enabled
+	Disabled []string `toml:"disabled" yaml:"disabled"`
+	// Timeout 为单次工具调用的执行时限，为 0 时不限时
+	Timeout time.Duration `toml:"timeout" yaml:"timeout"`
+}
+
+// ToolConfig 为单个工具的配置。
+type ToolConfig struct {
+	// Timeout 覆盖 ToolsConfig.Timeout，为 0 时沿用默认值
+	Timeout time.Duration `toml:"timeout" yaml:"timeout"`
+	// Defaults 为调用方未提供时使用的参数值，如 cpu_info 的采样时长 duration
+	Defaults map[string]any `toml:"defaults" yaml:"defaults"`
+}
+
+// Default 返回默认配置，与不使用配置文件时的行为一致。
+func Default() Config {
+	subscriptions := router.DefaultSubscriptionOptions()
+	return Config{
+		Transport:          "stdio",
+		Addr:               "127.0.0.1:8080",
+		Endpoint:           "/mcp",
+		SessionIdleTimeout: 30 * time.Minute,
+		MaxConcurrency:     16,
+		MaxMessageSize:     4 << 20,
+		KeepAlive: KeepAliveConfig{
+			Timeout: 10 * time.Second,
+		},
+		Output: OutputConfig{
+			Locale: string(i18n.Default),
+			Format: string(render.FormatText),
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Subscriptions: SubscriptionsConfig{
+			Interval:    subscriptions.Interval,
+			MinDelta:    subscriptions.MinDelta,
+			MaxInterval: subscriptions.MaxInterval,
+		},
+		Tools: ToolsConfig{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Load 在默认配置之上加载配置文件，按扩展名识别格式：.toml、.yaml 或 .yml。
+// 文件中出现未知的配置项时返回错误，以免拼写错误被静默忽略。
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		meta, err := toml.Decode(string(data), &cfg)
+		if err != nil {
+			return cfg, fmt.Errorf("解析配置文件 %s 失败: %v", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return cfg, fmt.Errorf("配置文件 %s 包含未知的配置项: %s", path, strings.Join(keys, ", "))
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// 空文件等同于没有任何配置项
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("解析配置文件 %s 失败: %v", path, err)
+		}
+	default:
+		return cfg, fmt.Errorf("不支持的配置文件格式 %q，应为 .toml、.yaml 或 .yml", ext)
+	}
+
+	return cfg, nil
+}
+
+// Validate 检查配置是否有效，工具名称与默认参数按 registry 中注册的工具校验。
+// 一次返回全部问题，便于在启动时一并修正。
+func (c Config) Validate(registry *tools.Registry) error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch c.Transport {
+	case "stdio":
+	case "http", "sse", "ws":
+		if c.Addr == "" {
+			fail("addr: %s 传输需要监听地址", c.Transport)
+		}
+	default:
+		fail("transport: 不支持的传输方式 %q，应为 stdio、http、sse 或 ws", c.Transport)
+	}
+	if !strings.HasPrefix(c.Endpoint, "/") {
+		fail("endpoint: 端点路径 %q 必须以 / 开头", c.Endpoint)
+	}
+	if c.SessionIdleTimeout < 0 {
+		fail("session_idle_timeout: 不能为负数")
+	}
+	if c.MaxConcurrency <= 0 {
+		fail("max_concurrency: 必须大于 0")
+	}
+	if c.MaxMessageSize <= 0 {
+		fail("max_message_size: 必须大于 0")
+	}
+
+	if c.KeepAlive.Interval < 0 {
+		fail("keepalive.interval: 不能为负数")
+	}
+	if c.KeepAlive.Interval > 0 && c.KeepAlive.Timeout <= 0 {
+		fail("keepalive.timeout: 启用 ping 时必须大于 0")
+	}
+
+	locale, ok := i18n.Parse(c.Output.Locale)
+	if !ok {
+		fail("output.locale: 不支持的语言 %q，应为 en 或 zh-CN", c.Output.Locale)
+		locale = i18n.Default
+	}
+	if _, err := render.ParseFormat(locale, c.Output.Format); err != nil {
+		fail("output.format: %v", err)
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		fail("log.level: 不支持的日志级别 %q，应为 debug、info、warn 或 error", c.Log.Level)
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		fail("log.format: 不支持的日志格式 %q，应为 text 或 json", c.Log.Format)
+	}
+
+	if c.Subscriptions.Interval <= 0 {
+		fail("subscriptions.interval: 必须大于 0")
+	}
+	if c.Subscriptions.MinDelta < 0 {
+		fail("subscriptions.min_delta: 不能为负数")
+	}
+	if c.Subscriptions.MaxInterval < 0 {
+		fail("subscriptions.max_interval: 不能为负数")
+	}
+
+	if c.Tools.Timeout < 0 {
+		fail("tools.timeout: 不能为负数")
+	}
+	for _, name := range c.Tools.Enabled {
+		if _, ok := registry.Lookup(name); !ok {
+			fail("tools.enabled: 未知的工具 %q", name)
+		}
+	}
+	for _, name := range c.Tools.Disabled {
+		if _, ok := registry.Lookup(name); !ok {
+			fail("tools.disabled: 未知的工具 %q", name)
+		}
+	}
+
+	// 按名称顺序检查，保证同样的配置总是以同样的顺序报告错误
+	names := make([]string, 0, len(c.Tool))
+	for name := range c.Tool {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tool, ok := registry.Lookup(name)
+		if !ok {
+			fail("tool.%s: 未知的工具", name)
+			continue
+		}
+		if c.Tool[name].Timeout < 0 {
+			fail("tool.%s.timeout: 不能为负数", name)
+		}
+		// 默认参数只需符合各参数自身的约束，必填参数仍由调用方提供
+		schema := tool.GetInputSchema(locale)
+		schema.Required = nil
+		if err := schema.Validate(c.Tool[name].Defaults); err != nil {
+			fail("tool.%s.defaults: %v", name, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Locale 返回配置的默认语言，配置无效时返回 i18n.Default。
+func (c Config) Locale() i18n.Locale {
+	if locale, ok := i18n.Parse(c.Output.Locale); ok {
+		return locale
+	}
+	return i18n.Default
+}
+
+// ToolDefaults 返回各工具的默认参数：先取 output.format 作为支持 format 参数的工具的默认格式，
+// 再叠加 tool.<name>.defaults 中的配置。
+func (c Config) ToolDefaults(registry *tools.Registry) map[string]map[string]any {
+	defaults := make(map[string]map[string]any)
+	for _, name := range registry.Names() {
+		tool, _ := registry.Lookup(name)
+		values := make(map[string]any)
+		if _, ok := tool.GetInputSchema(c.Locale()).Properties[render.FormatArgument]; ok && c.Output.Format != "" {
+			values[render.FormatArgument] = c.Output.Format
+		}
+		for key, value := range c.Tool[name].Defaults {
+			values[key] = value
+		}
+		if len(values) > 0 {
+			defaults[name] = values
+		}
+	}
+	return defaults
+}
+
+// ToolTimeouts 返回单独配置了执行时限的工具。
+func (c Config) ToolTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for name, tool := range c.Tool {
+		if tool.Timeout > 0 {
+			timeouts[name] = tool.Timeout
+		}
+	}
+	return timeouts
+}
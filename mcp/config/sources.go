@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix 为覆盖配置项的环境变量前缀，如 GO_MCP_TRANSPORT 覆盖 transport。
+const EnvPrefix = "GO_MCP_"
+
+// setting 描述一个可以由环境变量与命令行参数覆盖的配置项。
+type setting struct {
+	// flag 为命令行参数名，环境变量名由其转换而来：-log-level 对应 GO_MCP_LOG_LEVEL
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+	// get 返回配置项当前值的文字表示，用于在命令行帮助中显示默认值
+	get func(c *Config) string
+}
+
+// settings 为可以覆盖的全部配置项；按工具配置的默认参数只能在配置文件中设置
+var settings = []setting{
+	stringSetting("transport", "传输方式: stdio、http、sse（旧版 HTTP+SSE）或 ws", func(c *Config) *string { return &c.Transport }),
+	stringSetting("addr", "HTTP/SSE/WebSocket 传输的监听地址", func(c *Config) *string { return &c.Addr }),
+	stringSetting("endpoint", "HTTP/WebSocket 传输的 MCP 端点路径", func(c *Config) *string { return &c.Endpoint }),
+	listSetting("allowed-hosts", "本机地址之外允许访问 HTTP/SSE/WebSocket 传输的主机名，多个以逗号分隔", func(c *Config) *[]string { return &c.AllowedHosts }),
+	durationSetting("session-idle-timeout", "Streamable HTTP 会话空闲多久后自动结束，0 表示不过期", func(c *Config) *time.Duration { return &c.SessionIdleTimeout }),
+	intSetting("max-concurrency", "同时处理的请求数上限", func(c *Config) *int { return &c.MaxConcurrency }),
+	int64Setting("max-message-size", "单条 JSON-RPC 消息的字节数上限", func(c *Config) *int64 { return &c.MaxMessageSize }),
+	durationSetting("ping-interval", "服务器主动 ping 客户端的周期，0 表示不发送", func(c *Config) *time.Duration { return &c.KeepAlive.Interval }),
+	durationSetting("ping-timeout", "等待客户端应答 ping 的时长，超时即关闭会话", func(c *Config) *time.Duration { return &c.KeepAlive.Timeout }),
+	stringSetting("locale", "工具描述与输出的默认语言: en 或 zh-CN", func(c *Config) *string { return &c.Output.Locale }),
+	stringSetting("format", "工具输出的默认格式: text、markdown、json 或 csv", func(c *Config) *string { return &c.Output.Format }),
+	stringSetting("log-level", "服务器日志级别: debug、info、warn 或 error", func(c *Config) *string { return &c.Log.Level }),
+	stringSetting("log-format", "服务器日志格式: text 或 json", func(c *Config) *string { return &c.Log.Format }),
+	stringSetting("log-file", "服务器日志文件路径，为空时写入标准错误", func(c *Config) *string { return &c.Log.File }),
+	durationSetting("subscription-interval", "资源订阅的后台采样周期", func(c *Config) *time.Duration { return &c.Subscriptions.Interval }),
+	floatSetting("subscription-min-delta", "触发资源更新推送的最小指标变化量（百分点）", func(c *Config) *float64 { return &c.Subscriptions.MinDelta }),
+	durationSetting("subscription-max-interval", "两次资源更新推送之间的最长间隔，0 表示仅按变化量推送", func(c *Config) *time.Duration { return &c.Subscriptions.MaxInterval }),
+	listSetting("enable-tools", "启用的工具名称，多个以逗号分隔，为空时启用全部", func(c *Config) *[]string { return &c.Tools.Enabled }),
+	listSetting("disable-tools", "停用的工具名称，多个以逗号分隔", func(c *Config) *[]string { return &c.Tools.Disabled }),
+	durationSetting("tool-timeout", "单次工具调用的执行时限，0 表示不限时", func(c *Config) *time.Duration { return &c.Tools.Timeout }),
+}
+
+// EnvName 返回命令行参数对应的环境变量名。
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// ApplyEnv 以环境变量覆盖配置项，lookup 通常为 os.LookupEnv。返回全部无法解析的环境变量。
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, s := range settings {
+		name := EnvName(s.flag)
+		if value, ok := lookup(name); ok {
+			if err := s.set(c, value); err != nil {
+				errs = append(errs, fmt.Errorf("环境变量 %s: %v", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BindFlags 在 fs 上注册与配置项对应的命令行参数。命令行参数的优先级最高，但配置文件要在
+// 解析参数之后才能加载，因此解析时只记录参数值；返回的函数在加载配置文件与环境变量后调用，
+// 将显式指定的参数覆盖到配置上。
+func BindFlags(fs *flag.FlagSet) func(c *Config) error {
+	type value struct {
+		setting setting
+		raw     string
+	}
+	var values []value
+
+	defaults := Default()
+	for _, s := range settings {
+		usage := fmt.Sprintf("%s（环境变量 %s", s.usage, EnvName(s.flag))
+		if def := s.get(&defaults); def != "" {
+			usage += "，默认 " + def
+		}
+		fs.Func(s.flag, usage+"）", func(raw string) error {
+			// 提前校验格式，错误由 flag 包按惯例报告
+			var probe Config
+			if err := s.set(&probe, raw); err != nil {
+				return err
+			}
+			values = append(values, value{setting: s, raw: raw})
+			return nil
+		})
+	}
+
+	return func(c *Config) error {
+		for _, v := range values {
+			if err := v.setting.set(c, v.raw); err != nil {
+				return fmt.Errorf("参数 -%s: %v", v.setting.flag, err)
+			}
+		}
+		return nil
+	}
+}
+
+func stringSetting(flag, usage string, field func(*Config) *string) setting {
+	return setting{
+		flag:  flag,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+		get: func(c *Config) string { return *field(c) },
+	}
+}
+
+func intSetting(flag, usage string, field func(*Config) *int) setting {
+	return setting{
+		flag:  flag,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("无效的整数 %q", value)
+			}
+			*field(c) = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(*field(c)) },
+	}
+}
+
+func int64Setting(flag, usage string, field func(*Config) *int64) setting {
+	return setting{
+		flag:  flag,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("无效的整数 %q", value)
+			}
+			*field(c) = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatInt(*field(c), 10) },
+	}
+}
+
+func floatSetting(flag, usage string, field func(*Config) *float64) setting {
+	return setting{
+		flag:  flag,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("无效的数值 %q", value)
+			}
+			*field(c) = f
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatFloat(*field(c), 'g', -1, 64) },
+	}
+}
+
+func durationSetting(flag, usage string, field func(*Config) *time.Duration) setting {
+	return setting{
+		flag:  flag,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("无效的时长 %q，应形如 500ms、5s 或 1m", value)
+			}
+			*field(c) = d
+			return nil
+		},
+		get: func(c *Config) string { return field(c).String() },
+	}
+}
+
+// listSetting 创建以逗号分隔的列表配置项，忽略空白项
+func listSetting(flag, usage string, field func(*Config) *[]string) setting {
+	return setting{
+		flag:  flag,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			var items []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			*field(c) = items
+			return nil
+		},
+		get: func(c *Config) string { return strings.Join(*field(c), ",") },
+	}
+}
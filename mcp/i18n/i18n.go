@@ -0,0 +1,77 @@
+// Package i18n 提供工具描述、参数说明、输出标签与错误信息的多语言消息目录。
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Locale 表示一种输出语言，取值为 BCP 47 语言标签。
+type Locale string
+
+const (
+	// English 为英文。
+	English Locale = "en"
+	// Chinese 为简体中文，也是默认语言。
+	Chinese Locale = "zh-CN"
+)
+
+// Default 为未指定语言时使用的语言。
+const Default = Chinese
+
+// Argument 为工具调用中用于临时切换语言的参数名。
+const Argument = "locale"
+
+// Supported 列出消息目录支持的全部语言。
+var Supported = []Locale{English, Chinese}
+
+// Parse 将语言标签规范化为支持的语言，如 en-US → en、zh_Hans → zh-CN。
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	primary, _, _ := strings.Cut(tag, "-")
+
+	switch primary {
+	case "en":
+		return English, true
+	case "zh":
+		return Chinese, true
+	default:
+		return "", false
+	}
+}
+
+// T 返回指定语言下的消息，提供 args 时按 fmt 格式化；
+// 该语言缺少消息时回退到默认语言，仍然缺少时返回 key 本身。
+func T(locale Locale, key string, args ...any) string {
+	message, ok := catalogue[locale][key]
+	if !ok {
+		if message, ok = catalogue[Default][key]; !ok {
+			message = key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Errorf 返回指定语言下格式化的错误，%w 包装语义与 fmt.Errorf 相同。
+func Errorf(locale Locale, key string, args ...any) error {
+	return fmt.Errorf(T(locale, key), args...)
+}
+
+type localeKey struct{}
+
+// WithLocale 返回携带输出语言的 context。
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext 取出 context 中的输出语言，未设置时返回默认语言。
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return locale
+	}
+	return Default
+}
@@ -0,0 +1,320 @@
+package i18n
+
+// catalogue 按语言保存全部消息，键按“模块.用途”命名。
+var catalogue = map[Locale]map[string]string{
+	Chinese: {
+		// 通用参数与错误
+		"arg.format":    "输出格式: text（默认）、markdown、json 或 csv",
+		"arg.locale":    "输出语言: en 或 zh-CN，默认为会话协商的语言",
+		"error.format":  "不支持的输出格式: %s",
+		"error.locale":  "不支持的语言: %s",
+		"error.timeout": "工具 %s 执行超时（%s），可能有采集项（如卡住的网络挂载点）没有响应",
+
+		// 渲染器
+		"render.updated": "更新时间",
+		"render.name":    "名称",
+		"render.value":   "值",
+
+		// CPU
+		"cpu.toolTitle":        "CPU 监控",
+		"cpu.description":      "获取 CPU 使用率和详细信息",
+		"cpu.arg.duration":     "监控持续时间 (1s, 5s, 10s)",
+		"cpu.error.info":       "获取 CPU 信息失败: %v",
+		"cpu.error.basic":      "获取 CPU 基本信息失败: %v",
+		"cpu.error.perCore":    "获取 CPU 使用率失败: %v",
+		"cpu.error.total":      "获取总体 CPU 使用率失败: %v",
+		"cpu.progress.perCore": "采样各核心 CPU 使用率",
+		"cpu.progress.total":   "采样总体 CPU 使用率",
+		"cpu.title":            "CPU 信息",
+		"cpu.model":            "型号",
+		"cpu.cores":            "核心数",
+		"cpu.coresValue":       "%d 物理核心, %d 逻辑核心",
+		"cpu.frequency":        "主频",
+		"cpu.usageTitle":       "CPU 使用率 (监控时长: %s)",
+		"cpu.totalUsage":       "总体使用率",
+		"cpu.core":             "核心",
+		"cpu.coreN":            "核心 %d",
+		"cpu.usage":            "使用率",
+
+		// 磁盘
+		"disk.toolTitle":        "磁盘使用情况",
+		"disk.description":      "获取磁盘使用情况",
+		"disk.arg.showAll":      "是否显示所有分区（包括系统分区）",
+		"disk.error.info":       "获取磁盘信息失败: %v",
+		"disk.error.partitions": "获取磁盘分区失败: %v",
+		"disk.title":            "磁盘信息",
+		"disk.empty":            "未找到可用的磁盘分区",
+		"disk.mountpoint":       "挂载点",
+		"disk.fstype":           "文件系统",
+		"disk.size":             "总大小",
+		"disk.used":             "已使用",
+		"disk.free":             "可用",
+		"disk.usedPercent":      "使用率",
+		"disk.total":            "总计",
+
+		// 内存
+		"memory.toolTitle":     "内存使用情况",
+		"memory.description":   "获取内存使用情况详细信息",
+		"memory.error.info":    "获取内存信息失败: %v",
+		"memory.error.virtual": "获取虚拟内存信息失败: %v",
+		"memory.error.swap":    "获取交换内存信息失败: %v",
+		"memory.title":         "内存信息",
+		"memory.total":         "总内存",
+		"memory.used":          "已使用",
+		"memory.available":     "可用内存",
+		"memory.free":          "空闲内存",
+		"memory.buffers":       "缓冲区",
+		"memory.cached":        "缓存",
+		"memory.swapTitle":     "交换内存",
+		"memory.swapTotal":     "总交换",
+		"memory.swapFree":      "空闲交换",
+
+		// 网络
+		"network.toolTitle":           "网络状态",
+		"network.description":         "获取网络连接状态和传输速度",
+		"network.arg.showConnections": "是否显示连接详情",
+		"network.arg.interfaceFilter": "网络接口过滤器（为空则显示所有）",
+		"network.error.info":          "获取网络信息失败: %v",
+		"network.error.interfaces":    "获取网络接口统计失败: %v",
+		"network.title":               "网络状态",
+		"network.interfacesTitle":     "网络接口统计",
+		"network.interface":           "接口",
+		"network.sentMB":              "发送(MB)",
+		"network.recvMB":              "接收(MB)",
+		"network.packetsSent":         "发送包数",
+		"network.packetsRecv":         "接收包数",
+		"network.errorsOut":           "发送错误",
+		"network.errorsIn":            "接收错误",
+		"network.connectionsTitle":    "网络连接统计",
+		"network.totalConnections":    "总连接数",
+		"network.byStatus":            "按状态分类",
+		"network.byProtocol":          "按协议分类",
+		"network.detailsTitle":        "连接详情 (前20个)",
+		"network.protocol":            "协议",
+		"network.localIP":             "本地IP",
+		"network.port":                "端口",
+		"network.remoteIP":            "远程IP",
+		"network.status":              "状态",
+
+		// 进程
+		"process.toolTitle":   "进程排行",
+		"process.description": "获取 CPU 或内存占用最高的进程",
+		"process.arg.sortBy":  "排序方式: cpu 或 memory",
+		"process.arg.limit":   "返回进程数量",
+		"process.error.info":  "获取进程信息失败: %v",
+		"process.error.list":  "获取进程列表失败: %v",
+		"process.progress":    "扫描进程",
+		"process.titleMemory": "内存占用最高的 %d 个进程",
+		"process.titleCPU":    "CPU 占用最高的 %d 个进程",
+		"process.total":       "总进程数",
+		"process.name":        "进程名",
+		"process.memoryMB":    "内存(MB)",
+		"process.status":      "状态",
+
+		// 系统
+		"system.toolTitle":       "系统概览",
+		"system.description":     "获取系统综合概览信息",
+		"system.arg.includeLoad": "是否包含系统负载信息",
+		"system.error.info":      "获取系统信息失败: %v",
+		"system.error.host":      "获取主机信息失败: %v",
+		"system.title":           "系统概览",
+		"system.hostname":        "主机名",
+		"system.os":              "操作系统",
+		"system.platform":        "平台",
+		"system.kernel":          "内核版本",
+		"system.arch":            "架构",
+		"system.uptime":          "运行时间",
+		"system.uptimeValue":     "%d天 %d小时 %d分钟",
+		"system.processCount":    "进程数",
+		"system.loadTitle":       "系统负载",
+		"system.loadUnavailable": "系统负载信息在此平台暂不可用",
+
+		// 提示模板
+		"prompt.fetchFailed":             "获取失败: %v",
+		"prompt.highCPU.description":     "诊断 CPU 使用率过高：附带当前 CPU 使用率与 CPU 占用最高的进程",
+		"prompt.highCPU.arg.duration":    "CPU 采样时长 (1s, 5s, 10s)，默认 1s",
+		"prompt.highCPU.text":            "主机 CPU 使用率偏高，请根据以下实时监控数据分析原因：\n1. 判断是整体负载高还是个别核心饱和；\n2. 找出占用 CPU 最多的进程并判断是否异常；\n3. 给出排查步骤和缓解建议。\n\n",
+		"prompt.diskFull.description":    "排查磁盘空间不足：附带该挂载点及所有分区的使用情况",
+		"prompt.diskFull.arg.mountpoint": "空间不足的挂载点，如 / 或 /var",
+		"prompt.diskFull.text":           "挂载点 %s 的磁盘空间即将耗尽，请根据以下实时监控数据进行排查：\n1. 确认该挂载点的容量、已用空间和剩余空间；\n2. 推测可能占用空间的目录（日志、缓存、临时文件等）并给出检查命令；\n3. 给出安全的清理或扩容建议。\n\n",
+		"prompt.diskFull.target":         "目标挂载点",
+		"prompt.diskFull.details":        "挂载点: %s\n设备: %s\n文件系统: %s\n容量: %.2f GB\n已用: %.2f GB (%.1f%%)\n可用: %.2f GB",
+		"prompt.slowProcess.description": "分析进程运行缓慢的原因：附带该进程信息、系统 CPU 与内存状态",
+		"prompt.slowProcess.arg.pid":     "需要分析的进程 PID",
+		"prompt.slowProcess.text":        "进程 %d 运行缓慢，请根据以下实时监控数据分析原因：\n1. 判断该进程是否受 CPU、内存或系统整体负载限制；\n2. 与其他高占用进程进行对比；\n3. 给出进一步诊断（如 strace、perf、pprof）和优化建议。\n\n",
+		"prompt.slowProcess.target":      "目标进程",
+		"prompt.slowProcess.details":     "PID: %d\n进程名: %s\n状态: %s\nCPU: %.2f%%\n内存: %.2f MB",
+
+		// 资源
+		"resource.system":             "系统基本信息（主机名、操作系统、内核、运行时间）",
+		"resource.cpu":                "CPU 型号、核心数及采样 1 秒的使用率",
+		"resource.memory":             "物理内存与交换内存使用情况",
+		"resource.disk":               "所有常规磁盘分区的使用情况",
+		"resource.network":            "各网络接口的流量统计",
+		"resource.processes":          "内存占用最高的进程列表",
+		"resource.diskByMountpoint":   "指定挂载点的磁盘使用情况，挂载点需经 URL 编码",
+		"resource.networkByInterface": "指定网络接口的流量统计",
+		"resource.processByPID":       "指定 PID 的进程信息",
+		"resource.error.pid":          "%w: 无效的 PID %q",
+		"resource.error.interface":    "%w: 找不到网络接口 %s",
+		"resource.error.encode":       "编码资源 %s 失败: %v",
+	},
+
+	English: {
+		// 通用参数与错误
+		"arg.format":    "Output format: text (default), markdown, json or csv",
+		"arg.locale":    "Output language: en or zh-CN; defaults to the session language",
+		"error.format":  "unsupported output format: %s",
+		"error.locale":  "unsupported locale: %s",
+		"error.timeout": "tool %s timed out after %s; a collector (such as a stuck network mount) may be hanging",
+
+		// 渲染器
+		"render.updated": "Updated",
+		"render.name":    "Name",
+		"render.value":   "Value",
+
+		// CPU
+		"cpu.toolTitle":        "CPU Monitor",
+		"cpu.description":      "Get CPU usage and details",
+		"cpu.arg.duration":     "Sampling duration (1s, 5s, 10s)",
+		"cpu.error.info":       "failed to get CPU information: %v",
+		"cpu.error.basic":      "failed to get CPU details: %v",
+		"cpu.error.perCore":    "failed to get per-core CPU usage: %v",
+		"cpu.error.total":      "failed to get total CPU usage: %v",
+		"cpu.progress.perCore": "Sampling per-core CPU usage",
+		"cpu.progress.total":   "Sampling total CPU usage",
+		"cpu.title":            "CPU Information",
+		"cpu.model":            "Model",
+		"cpu.cores":            "Cores",
+		"cpu.coresValue":       "%d physical, %d logical",
+		"cpu.frequency":        "Frequency",
+		"cpu.usageTitle":       "CPU Usage (sampled over %s)",
+		"cpu.totalUsage":       "Total usage",
+		"cpu.core":             "Core",
+		"cpu.coreN":            "Core %d",
+		"cpu.usage":            "Usage",
+
+		// 磁盘
+		"disk.toolTitle":        "Disk Usage",
+		"disk.description":      "Get disk usage",
+		"disk.arg.showAll":      "Whether to include all partitions, including system partitions",
+		"disk.error.info":       "failed to get disk information: %v",
+		"disk.error.partitions": "failed to list disk partitions: %v",
+		"disk.title":            "Disk Information",
+		"disk.empty":            "No usable disk partitions found",
+		"disk.mountpoint":       "Mountpoint",
+		"disk.fstype":           "Filesystem",
+		"disk.size":             "Size",
+		"disk.used":             "Used",
+		"disk.free":             "Available",
+		"disk.usedPercent":      "Use%",
+		"disk.total":            "Total",
+
+		// 内存
+		"memory.toolTitle":     "Memory Usage",
+		"memory.description":   "Get detailed memory usage",
+		"memory.error.info":    "failed to get memory information: %v",
+		"memory.error.virtual": "failed to get virtual memory statistics: %v",
+		"memory.error.swap":    "failed to get swap statistics: %v",
+		"memory.title":         "Memory Information",
+		"memory.total":         "Total",
+		"memory.used":          "Used",
+		"memory.available":     "Available",
+		"memory.free":          "Free",
+		"memory.buffers":       "Buffers",
+		"memory.cached":        "Cached",
+		"memory.swapTitle":     "Swap",
+		"memory.swapTotal":     "Total",
+		"memory.swapFree":      "Free",
+
+		// 网络
+		"network.toolTitle":           "Network Statistics",
+		"network.description":         "Get network connection status and traffic",
+		"network.arg.showConnections": "Whether to include connection details",
+		"network.arg.interfaceFilter": "Only show this network interface (empty for all)",
+		"network.error.info":          "failed to get network information: %v",
+		"network.error.interfaces":    "failed to get interface statistics: %v",
+		"network.title":               "Network Status",
+		"network.interfacesTitle":     "Interface Statistics",
+		"network.interface":           "Interface",
+		"network.sentMB":              "Sent (MB)",
+		"network.recvMB":              "Received (MB)",
+		"network.packetsSent":         "Packets Sent",
+		"network.packetsRecv":         "Packets Received",
+		"network.errorsOut":           "Send Errors",
+		"network.errorsIn":            "Receive Errors",
+		"network.connectionsTitle":    "Connections",
+		"network.totalConnections":    "Total connections",
+		"network.byStatus":            "By Status",
+		"network.byProtocol":          "By Protocol",
+		"network.detailsTitle":        "Connection Details (first 20)",
+		"network.protocol":            "Protocol",
+		"network.localIP":             "Local IP",
+		"network.port":                "Port",
+		"network.remoteIP":            "Remote IP",
+		"network.status":              "Status",
+
+		// 进程
+		"process.toolTitle":   "Top Processes",
+		"process.description": "Get the processes using the most CPU or memory",
+		"process.arg.sortBy":  "Sort by: cpu or memory",
+		"process.arg.limit":   "Number of processes to return",
+		"process.error.info":  "failed to get process information: %v",
+		"process.error.list":  "failed to list processes: %v",
+		"process.progress":    "Scanning processes",
+		"process.titleMemory": "Top %d Processes by Memory",
+		"process.titleCPU":    "Top %d Processes by CPU",
+		"process.total":       "Total processes",
+		"process.name":        "Name",
+		"process.memoryMB":    "Memory (MB)",
+		"process.status":      "Status",
+
+		// 系统
+		"system.toolTitle":       "System Overview",
+		"system.description":     "Get a system overview",
+		"system.arg.includeLoad": "Whether to include system load",
+		"system.error.info":      "failed to get system information: %v",
+		"system.error.host":      "failed to get host information: %v",
+		"system.title":           "System Overview",
+		"system.hostname":        "Hostname",
+		"system.os":              "OS",
+		"system.platform":        "Platform",
+		"system.kernel":          "Kernel",
+		"system.arch":            "Architecture",
+		"system.uptime":          "Uptime",
+		"system.uptimeValue":     "%dd %dh %dm",
+		"system.processCount":    "Processes",
+		"system.loadTitle":       "System Load",
+		"system.loadUnavailable": "System load is not available on this platform",
+
+		// 提示模板
+		"prompt.fetchFailed":             "failed to collect: %v",
+		"prompt.highCPU.description":     "Diagnose high CPU usage, with current CPU usage and the top CPU-consuming processes",
+		"prompt.highCPU.arg.duration":    "CPU sampling duration (1s, 5s, 10s), default 1s",
+		"prompt.highCPU.text":            "CPU usage on this host is high. Analyse the cause using the live monitoring data below:\n1. Determine whether overall load is high or individual cores are saturated;\n2. Identify the processes using the most CPU and whether they look abnormal;\n3. Suggest troubleshooting steps and mitigations.\n\n",
+		"prompt.diskFull.description":    "Investigate a full disk, with the usage of the mountpoint and every partition",
+		"prompt.diskFull.arg.mountpoint": "Mountpoint running out of space, such as / or /var",
+		"prompt.diskFull.text":           "The filesystem mounted at %s is running out of space. Investigate using the live monitoring data below:\n1. Confirm the capacity, used and available space of this mountpoint;\n2. Suggest directories likely to be using the space (logs, caches, temporary files) and commands to check them;\n3. Recommend safe clean-up or expansion options.\n\n",
+		"prompt.diskFull.target":         "Target Mountpoint",
+		"prompt.diskFull.details":        "Mountpoint: %s\nDevice: %s\nFilesystem: %s\nTotal: %.2f GB\nUsed: %.2f GB (%.1f%%)\nFree: %.2f GB",
+		"prompt.slowProcess.description": "Analyse why a process is slow, with the process details and system CPU and memory state",
+		"prompt.slowProcess.arg.pid":     "PID of the process to analyse",
+		"prompt.slowProcess.text":        "Process %d is running slowly. Analyse the cause using the live monitoring data below:\n1. Determine whether it is limited by CPU, memory or overall system load;\n2. Compare it with other heavy processes;\n3. Suggest further diagnostics (such as strace, perf or pprof) and optimisations.\n\n",
+		"prompt.slowProcess.target":      "Target Process",
+		"prompt.slowProcess.details":     "PID: %d\nName: %s\nStatus: %s\nCPU: %.2f%%\nMemory: %.2f MB",
+
+		// 资源
+		"resource.system":             "Basic system information (hostname, OS, kernel, uptime)",
+		"resource.cpu":                "CPU model, core count and usage sampled over 1 second",
+		"resource.memory":             "Physical and swap memory usage",
+		"resource.disk":               "Usage of all regular disk partitions",
+		"resource.network":            "Traffic statistics for each network interface",
+		"resource.processes":          "Processes using the most memory",
+		"resource.diskByMountpoint":   "Disk usage of a mountpoint; the mountpoint must be URL-encoded",
+		"resource.networkByInterface": "Traffic statistics for a network interface",
+		"resource.processByPID":       "Details of the process with the given PID",
+		"resource.error.pid":          "%w: invalid PID %q",
+		"resource.error.interface":    "%w: network interface %s not found",
+		"resource.error.encode":       "failed to encode resource %s: %v",
+	},
+}
@@ -0,0 +1,64 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"strings"
+)
+
+// renderCSV 将报告的每一节输出为一个 CSV 块：表格原样输出，字段列表输出为“名称,值”两列，
+// 块之间以空行分隔，最后一块为汇总信息与更新时间
+func renderCSV(report Report) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+
+	blocks := 0
+	writeBlock := func(records [][]string) {
+		if len(records) == 0 {
+			return
+		}
+		if blocks > 0 {
+			// csv.Writer 不支持写出空记录，空行需要在刷新后直接写入
+			w.Flush()
+			b.WriteString("\n")
+		}
+		w.WriteAll(records)
+		blocks++
+	}
+
+	for _, section := range report.Sections {
+		if len(section.Fields) > 0 {
+			writeBlock(fieldRecords(report.Locale, section.Fields))
+		}
+		if section.Table != nil {
+			records := [][]string{section.Table.Columns}
+			records = append(records, section.Table.Rows...)
+			if len(section.Table.Footer) > 0 {
+				records = append(records, section.Table.Footer)
+			}
+			writeBlock(records)
+		}
+	}
+
+	summary := append(append([]Field{}, report.Summary...), Field{
+		Label: i18n.T(report.Locale, "render.updated"),
+		Value: report.Updated.Format(timeLayout),
+	})
+	writeBlock(fieldRecords(report.Locale, summary))
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("编码 CSV 输出失败: %v", err)
+	}
+	return b.String(), nil
+}
+
+// fieldRecords 将字段列表转换为带表头的两列记录
+func fieldRecords(locale i18n.Locale, fields []Field) [][]string {
+	records := [][]string{{i18n.T(locale, "render.name"), i18n.T(locale, "render.value")}}
+	for _, field := range fields {
+		records = append(records, []string{field.Label, field.Value})
+	}
+	return records
+}
@@ -0,0 +1,77 @@
+package render
+
+import (
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"strings"
+)
+
+// renderMarkdown 以 Markdown 标题、字段列表和表格排版报告
+func renderMarkdown(report Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n\n", heading(report.Icon, report.Title))
+
+	for _, section := range report.Sections {
+		if section.Title != "" {
+			fmt.Fprintf(&b, "### %s\n\n", heading(section.Icon, section.Title))
+		}
+
+		if len(section.Fields) > 0 {
+			for _, field := range section.Fields {
+				fmt.Fprintf(&b, "- **%s**: %s\n", escapeMarkdown(field.Label), escapeMarkdown(field.Value))
+			}
+			b.WriteString("\n")
+		}
+		if section.Table != nil {
+			writeMarkdownTable(&b, section.Table)
+			b.WriteString("\n")
+		}
+		if section.Note != "" {
+			fmt.Fprintf(&b, "> %s\n\n", escapeMarkdown(section.Note))
+		}
+	}
+
+	for _, field := range report.Summary {
+		fmt.Fprintf(&b, "**%s**: %s  \n", escapeMarkdown(field.Label), escapeMarkdown(field.Value))
+	}
+	fmt.Fprintf(&b, "_%s: %s_\n", i18n.T(report.Locale, "render.updated"), report.Updated.Format(timeLayout))
+
+	return b.String()
+}
+
+// writeMarkdownTable 写出 GFM 表格，合计行加粗显示
+func writeMarkdownTable(b *strings.Builder, table *Table) {
+	writeMarkdownRow(b, table.Columns, false)
+
+	b.WriteString("|")
+	for range table.Columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+
+	for _, row := range table.Rows {
+		writeMarkdownRow(b, row, false)
+	}
+	if len(table.Footer) > 0 {
+		writeMarkdownRow(b, table.Footer, true)
+	}
+}
+
+// writeMarkdownRow 写出表格的一行
+func writeMarkdownRow(b *strings.Builder, cells []string, bold bool) {
+	b.WriteString("|")
+	for _, cell := range cells {
+		cell = escapeMarkdown(cell)
+		if bold && cell != "" {
+			cell = "**" + cell + "**"
+		}
+		fmt.Fprintf(b, " %s |", cell)
+	}
+	b.WriteString("\n")
+}
+
+// escapeMarkdown 转义会破坏表格或强调语法的字符
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`, "\n", " ").Replace(s)
+}
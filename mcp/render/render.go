@@ -0,0 +1,131 @@
+// Package render 将监控工具的结果渲染为文本、Markdown、JSON 或 CSV。
+// 工具只需把 types.*Info 结构描述为与格式无关的 Report，具体排版由各渲染器完成。
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/types"
+	"strings"
+	"time"
+)
+
+// Format 表示工具输出的格式。
+type Format string
+
+const (
+	// FormatText 为带图标与分隔线的纯文本，是默认格式。
+	FormatText Format = "text"
+	// FormatMarkdown 以 Markdown 标题、列表和表格输出，适合交给大模型阅读。
+	FormatMarkdown Format = "markdown"
+	// FormatJSON 直接输出结构化数据的 JSON 编码，适合脚本处理。
+	FormatJSON Format = "json"
+	// FormatCSV 以逗号分隔值输出各个表格，适合导入电子表格。
+	FormatCSV Format = "csv"
+)
+
+// FormatArgument 为所有工具共用的输出格式参数名。
+const FormatArgument = "format"
+
+// timeLayout 为报告中时间的显示格式。
+const timeLayout = "2006-01-02 15:04:05"
+
+// formats 按文档顺序列出支持的全部格式。
+var formats = []Format{FormatText, FormatMarkdown, FormatJSON, FormatCSV}
+
+// Report 描述一次工具输出的内容，与具体格式无关。
+type Report struct {
+	// Locale 决定渲染器自身文字（如更新时间）的语言，其余文字由工具按同一语言填写
+	Locale   i18n.Locale
+	Icon     string
+	Title    string
+	Sections []Section
+	// Summary 为报告末尾的汇总信息（如总进程数），显示在更新时间之前
+	Summary []Field
+	Updated time.Time
+}
+
+// Section 是报告中的一节，可以同时包含字段列表、表格和说明文字。
+type Section struct {
+	Icon   string
+	Title  string
+	Fields []Field
+	Table  *Table
+	// Note 为补充说明，如数据为空或在当前平台不可用时的提示
+	Note string
+}
+
+// Field 为一个“名称: 值”形式的字段。
+type Field struct {
+	Label string
+	Value string
+}
+
+// Table 为一个二维表格，Footer 非空时作为合计行显示在表格末尾。
+type Table struct {
+	Columns []string
+	Rows    [][]string
+	Footer  []string
+}
+
+// ParseFormat 解析格式名称，空字符串表示默认的文本格式；错误信息使用 locale 对应的语言。
+func ParseFormat(locale i18n.Locale, name string) (Format, error) {
+	if name == "" {
+		return FormatText, nil
+	}
+	for _, f := range formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", i18n.Errorf(locale, "error.format", name)
+}
+
+// FormatFromArgs 从工具参数中读取输出格式。
+func FormatFromArgs(locale i18n.Locale, args map[string]interface{}) (Format, error) {
+	name, _ := args[FormatArgument].(string)
+	return ParseFormat(locale, name)
+}
+
+// FormatProperty 返回所有工具共用的 format 参数定义。
+func FormatProperty(locale i18n.Locale) types.Property {
+	enum := make([]any, len(formats))
+	for i, f := range formats {
+		enum[i] = string(f)
+	}
+	return types.Property{
+		Type:        "string",
+		Description: i18n.T(locale, "arg.format"),
+		Enum:        enum,
+		Default:     string(FormatText),
+	}
+}
+
+// Render 按指定格式输出报告；JSON 格式直接编码 data，其余格式排版 report。
+func Render(format Format, report Report, data any) (string, error) {
+	switch format {
+	case FormatText:
+		return renderText(report), nil
+	case FormatMarkdown:
+		return renderMarkdown(report), nil
+	case FormatJSON:
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("编码 JSON 输出失败: %v", err)
+		}
+		return string(encoded) + "\n", nil
+	case FormatCSV:
+		return renderCSV(report)
+	default:
+		return "", i18n.Errorf(report.Locale, "error.format", format)
+	}
+}
+
+// heading 拼接图标与标题
+func heading(icon, title string) string {
+	if icon == "" {
+		return title
+	}
+	return icon + " " + title
+}
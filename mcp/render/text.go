@@ -0,0 +1,133 @@
+package render
+
+import (
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"strings"
+)
+
+// minRuleWidth 为分隔线的最小显示宽度。
+const minRuleWidth = 40
+
+// renderText 以带图标与分隔线的纯文本排版报告，表格按列宽对齐
+func renderText(report Report) string {
+	var b strings.Builder
+	width := ruleWidth(report)
+	rule := strings.Repeat("━", width) + "\n"
+
+	b.WriteString(heading(report.Icon, report.Title) + "\n")
+	b.WriteString(rule)
+
+	for i, section := range report.Sections {
+		if section.Title != "" {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(heading(section.Icon, section.Title) + "\n")
+			b.WriteString(rule)
+		}
+
+		for _, field := range section.Fields {
+			fmt.Fprintf(&b, "%s: %s\n", field.Label, field.Value)
+		}
+		if section.Table != nil {
+			writeTextTable(&b, section.Table, rule)
+		}
+		if section.Note != "" {
+			b.WriteString(section.Note + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	for _, field := range report.Summary {
+		fmt.Fprintf(&b, "📊 %s: %s\n", field.Label, field.Value)
+	}
+	fmt.Fprintf(&b, "📅 %s: %s\n", i18n.T(report.Locale, "render.updated"), report.Updated.Format(timeLayout))
+
+	return b.String()
+}
+
+// writeTextTable 写出对齐的表头、数据行与合计行
+func writeTextTable(b *strings.Builder, table *Table, rule string) {
+	widths := columnWidths(table)
+
+	writeTextRow(b, table.Columns, widths)
+	b.WriteString(rule)
+	for _, row := range table.Rows {
+		writeTextRow(b, row, widths)
+	}
+	if len(table.Footer) > 0 {
+		b.WriteString(rule)
+		writeTextRow(b, table.Footer, widths)
+	}
+}
+
+// writeTextRow 按列宽左对齐写出一行，最后一列不补空格
+func writeTextRow(b *strings.Builder, cells []string, widths []int) {
+	for i, cell := range cells {
+		b.WriteString(cell)
+		if i < len(cells)-1 {
+			b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+1))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// columnWidths 计算每列的最大显示宽度
+func columnWidths(table *Table) []int {
+	widths := make([]int, len(table.Columns))
+	measure := func(cells []string) {
+		for i, cell := range cells {
+			if i < len(widths) {
+				widths[i] = max(widths[i], displayWidth(cell))
+			}
+		}
+	}
+
+	measure(table.Columns)
+	for _, row := range table.Rows {
+		measure(row)
+	}
+	measure(table.Footer)
+	return widths
+}
+
+// ruleWidth 取最宽表格的宽度作为分隔线宽度，使分隔线与表格对齐
+func ruleWidth(report Report) int {
+	width := minRuleWidth
+	for _, section := range report.Sections {
+		if section.Table == nil {
+			continue
+		}
+		total := 0
+		for _, w := range columnWidths(section.Table) {
+			total += w + 1
+		}
+		width = max(width, total-1)
+	}
+	return width
+}
+
+// displayWidth 估算字符串在等宽终端中的显示宽度，中日韩等全角字符按两列计算
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if isWide(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// isWide 判断字符是否为全角字符
+func isWide(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115F) ||
+		(r >= 0x2E80 && r <= 0xA4CF) ||
+		(r >= 0xAC00 && r <= 0xD7A3) ||
+		(r >= 0xF900 && r <= 0xFAFF) ||
+		(r >= 0xFE30 && r <= 0xFE4F) ||
+		(r >= 0xFF00 && r <= 0xFF60) ||
+		(r >= 0xFFE0 && r <= 0xFFE6)
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-mcp/mcp/types"
+	"sync"
+)
+
+// nullID 是无法确定请求 ID 时响应中使用的 null ID。
+var nullID = json.RawMessage("null")
+
+// isBatch 判断原始消息是否为 JSON-RPC 批量请求（JSON 数组）
+func isBatch(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// processBatch 处理一个批量请求：通知依次立即处理，请求经工作池并发执行，
+// 全部完成后按原顺序汇总响应。返回值为需要回复的单个错误响应、响应数组，
+// 或在批量中只有通知时返回 nil。sink 的含义与 processRequest 相同。
+func (s *Server) processBatch(sess *session, data []byte, sink messageSink) any {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return &types.Response{
+			JSONRPC: "2.0",
+			ID:      nullID,
+			Error:   types.NewError(types.CodeParseError, "Parse error: "+err.Error()),
+		}
+	}
+
+	// 空数组不是合法的批量请求，按规范返回单个错误而不是数组
+	if len(elements) == 0 {
+		return &types.Response{
+			JSONRPC: "2.0",
+			ID:      nullID,
+			Error:   types.NewError(types.CodeInvalidRequest, "Invalid Request: empty batch"),
+		}
+	}
+
+	// 2025-06-18 起协议移除了批量请求，此前的版本（2024-11-05 与 2025-03-26）均接受
+	if !sess.features().batch {
+		return &types.Response{
+			JSONRPC: "2.0",
+			ID:      nullID,
+			Error: types.NewErrorf(types.CodeInvalidRequest, "Invalid Request: batch requests are not supported in protocol version %s",
+				sess.activeVersion()).WithData(types.ErrorData{Expected: "object"}),
+		}
+	}
+
+	sess.wg.Add(1)
+	defer sess.wg.Done()
+
+	responses := make([]*types.Response, len(elements))
+	var wg sync.WaitGroup
+	for i, element := range elements {
+		if response, ok := decodeResponse(element); ok {
+			sess.deliver(response)
+			continue
+		}
+
+		req, errResp := decodeMessage(element)
+		if errResp != nil {
+			responses[i] = errResp
+			continue
+		}
+		if req == nil {
+			continue
+		}
+
+		if req.ID == nil {
+			s.handleRequest(sess.ctx, sess, req)
+			continue
+		}
+
+		ctx, ok := sess.track(req.ID)
+		if !ok {
+			responses[i] = s.duplicateIDResponse(req)
+			continue
+		}
+		ctx, errResp = s.admitRequest(ctx, sess, req)
+		if errResp != nil {
+			sess.untrack(req.ID)
+			responses[i] = errResp
+			continue
+		}
+		if sink != nil {
+			ctx = withRequestSink(ctx, sink)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer sess.untrack(req.ID)
+
+			response := s.execute(ctx, sess, req)
+			// 已被客户端取消的请求不出现在批量响应中
+			if !cancelledByClient(ctx) {
+				responses[i] = response
+			}
+		}()
+	}
+	wg.Wait()
+
+	var replies []*types.Response
+	for _, response := range responses {
+		if response != nil {
+			replies = append(replies, response)
+		}
+	}
+	if len(replies) == 0 {
+		return nil
+	}
+	return replies
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"encoding/json"
+	"go-mcp/mcp/types"
+	"strings"
+	"testing"
+)
+
+// legacyInitializeLine 协商 2025-03-26，该版本仍接受批量请求
+var legacyInitializeLine = strings.Replace(initializeLine, `"2025-06-18"`, `"2025-03-26"`, 1)
+
+// runBatch 在协商 2025-03-26 的会话上处理一个批量请求，返回握手之后的原始输出行
+func runBatch(t *testing.T, batch string) []string {
+	t.Helper()
+
+	lines := runLines(t, newTestServer, legacyInitializeLine, initializedLine, batch)
+	if len(lines) == 0 {
+		t.Fatal("no initialize response")
+	}
+	var init struct {
+		Result types.InitializeResult `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &init); err != nil {
+		t.Fatalf("invalid initialize response %q: %v", lines[0], err)
+	}
+	if init.Result.ProtocolVersion != "2025-03-26" {
+		t.Fatalf("negotiated %q, want 2025-03-26", init.Result.ProtocolVersion)
+	}
+	return lines[1:]
+}
+
+func TestEmptyBatch(t *testing.T) {
+	lines := runBatch(t, `[]`)
+	if len(lines) != 1 {
+		t.Fatalf("got %d messages, want 1: %q", len(lines), lines)
+	}
+	var response testResponse
+	if err := json.Unmarshal([]byte(lines[0]), &response); err != nil {
+		t.Fatalf("empty batch must get a single response, got %q: %v", lines[0], err)
+	}
+	expectError(t, response, types.CodeInvalidRequest, "null")
+}
+
+func TestNotificationBatchIsNotAnswered(t *testing.T) {
+	lines := runBatch(t, `[{"jsonrpc":"2.0","method":"notifications/initialized"},`+
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":9}}]`)
+	if len(lines) != 0 {
+		t.Fatalf("got %d messages for a batch of notifications, want none: %q", len(lines), lines)
+	}
+}
+
+func TestMixedBatch(t *testing.T) {
+	lines := runBatch(t, `[{"jsonrpc":"2.0","id":1,"method":"ping"},`+
+		`{"jsonrpc":"2.0","method":"notifications/initialized"},`+
+		`{"jsonrpc":"2.0","id":"b","method":"tools/list"},`+
+		`1]`)
+	if len(lines) != 1 {
+		t.Fatalf("got %d messages, want a single array: %q", len(lines), lines)
+	}
+	var responses []testResponse
+	if err := json.Unmarshal([]byte(lines[0]), &responses); err != nil {
+		t.Fatalf("batch response is not an array %q: %v", lines[0], err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want one per request: %+v", len(responses), responses)
+	}
+
+	for i, id := range []string{"1", `"b"`} {
+		if got := string(responses[i].ID); got != id {
+			t.Errorf("responses[%d].id = %s, want %s", i, got, id)
+		}
+		if responses[i].Error != nil {
+			t.Errorf("responses[%d] failed: %s", i, responses[i].Error.Message)
+		}
+	}
+	expectError(t, responses[2], types.CodeInvalidRequest, "null")
+	if got := responses[2].Error.Data.Expected; got != "object" {
+		t.Errorf("error.data.expected = %q, want object", got)
+	}
+}
+
+func TestBatchRejectedAfterRemoval(t *testing.T) {
+	// 2025-06-18 不再接受批量请求，数组整体作为一个无效请求回复
+	response := single(t, runInitialized(t, `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`))
+	expectError(t, response, types.CodeInvalidRequest, "null")
+	if !strings.Contains(response.Error.Message, "batch requests are not supported") {
+		t.Errorf("error.message = %q, want batch rejection", response.Error.Message)
+	}
+}
@@ -0,0 +1,74 @@
+package router
+
+import "go-mcp/mcp/types"
+
+// Capabilities 控制服务器声明并提供的 MCP 能力。关闭的能力不会出现在 initialize 结果中，
+// 对应的方法按未知方法处理。
+type Capabilities struct {
+	Tools bool
+	// ToolsListChanged 表示工具注册表变化时是否推送 notifications/tools/list_changed
+	ToolsListChanged bool
+
+	Resources bool
+	// ResourceSubscribe 表示是否支持 resources/subscribe 与 resources/unsubscribe
+	ResourceSubscribe bool
+	// ResourcesListChanged 表示资源因数据来源工具的启停而增减时是否推送 notifications/resources/list_changed
+	ResourcesListChanged bool
+
+	Prompts bool
+	// PromptsListChanged 表示提示模板因所需工具的启停而增减时是否推送 notifications/prompts/list_changed
+	PromptsListChanged bool
+
+	// Logging 表示是否支持 logging/setLevel，并以 notifications/message 向客户端推送服务器日志
+	Logging bool
+}
+
+// DefaultCapabilities 返回默认开启全部能力的配置。
+func DefaultCapabilities() Capabilities {
+	return Capabilities{
+		Tools:                true,
+		ToolsListChanged:     true,
+		Resources:            true,
+		ResourceSubscribe:    true,
+		ResourcesListChanged: true,
+		Prompts:              true,
+		PromptsListChanged:   true,
+		Logging:              true,
+	}
+}
+
+// declared 返回 initialize 结果中声明的能力
+func (c Capabilities) declared() types.ServerCapabilities {
+	var declared types.ServerCapabilities
+	if c.Tools {
+		declared.Tools = &types.ToolsCapability{ListChanged: c.ToolsListChanged}
+	}
+	if c.Resources {
+		declared.Resources = &types.ResourcesCapability{Subscribe: c.ResourceSubscribe, ListChanged: c.ResourcesListChanged}
+	}
+	if c.Prompts {
+		declared.Prompts = &types.PromptsCapability{ListChanged: c.PromptsListChanged}
+	}
+	if c.Logging {
+		declared.Logging = &types.LoggingCapability{}
+	}
+	return declared
+}
+
+// allows 判断方法所属的能力是否开启，不属于任何能力的方法（如 ping）总是允许
+func (c Capabilities) allows(method string) bool {
+	switch method {
+	case types.MethodListTools, types.MethodCallTool:
+		return c.Tools
+	case types.MethodListResources, types.MethodListResourceTemplates, types.MethodReadResource:
+		return c.Resources
+	case types.MethodSubscribeResource, types.MethodUnsubscribeResource:
+		return c.Resources && c.ResourceSubscribe
+	case types.MethodListPrompts, types.MethodGetPrompt:
+		return c.Prompts
+	case types.MethodSetLevel:
+		return c.Logging
+	default:
+		return true
+	}
+}
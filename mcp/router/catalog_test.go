@@ -0,0 +1,257 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/tools"
+	"go-mcp/mcp/types"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordSink 记录会话发出的通知
+type recordSink struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (r *recordSink) write(message any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := message.(*types.Notification); ok {
+		r.methods = append(r.methods, n.Method)
+	}
+	return nil
+}
+
+// take 返回并清空已记录的通知方法
+func (r *recordSink) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	methods := r.methods
+	r.methods = nil
+	return methods
+}
+
+func resourceURIs(c *resourceCatalog) []string {
+	var uris []string
+	for _, r := range c.resources(i18n.Default) {
+		uris = append(uris, r.URI)
+	}
+	for _, t := range c.resourceTemplates(i18n.Default) {
+		uris = append(uris, t.URITemplate)
+	}
+	return uris
+}
+
+func promptNames(c *promptCatalog) []string {
+	var names []string
+	for _, p := range c.list(i18n.Default) {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestCatalogsFollowRegistry(t *testing.T) {
+	server := NewServer()
+	registry := server.Registry()
+
+	registry.Disable("top_processes")
+
+	uris := resourceURIs(server.resources)
+	for _, uri := range []string{"sysmon://processes", "sysmon://process/{pid}"} {
+		if slices.Contains(uris, uri) {
+			t.Errorf("resource %s listed while top_processes is disabled", uri)
+		}
+	}
+	if !slices.Contains(uris, "sysmon://memory") {
+		t.Errorf("resource sysmon://memory missing: %v", uris)
+	}
+	for _, uri := range []string{"sysmon://processes", "sysmon://process/1"} {
+		if _, err := server.resources.resolve(uri); !errors.Is(err, errResourceNotFound) {
+			t.Errorf("resolve(%s) = %v, want errResourceNotFound", uri, err)
+		}
+	}
+
+	names := promptNames(server.prompts)
+	if want := []string{"investigate_disk_full"}; !slices.Equal(names, want) {
+		t.Errorf("prompts = %v, want %v", names, want)
+	}
+	if _, err := server.prompts.get(context.Background(), "diagnose_slow_process", map[string]string{"pid": "1"}, server.runTool); !errors.Is(err, errPromptNotFound) {
+		t.Errorf("get disabled prompt = %v, want errPromptNotFound", err)
+	}
+
+	registry.Enable("top_processes")
+	if _, err := server.resources.resolve("sysmon://process/1"); err != nil {
+		t.Errorf("resolve after enable: %v", err)
+	}
+	if got := len(server.prompts.list(i18n.Default)); got != 3 {
+		t.Errorf("%d prompts after enable, want 3", got)
+	}
+}
+
+func TestRegistryChangeNotifiesLists(t *testing.T) {
+	server := NewServer()
+	sink := &recordSink{}
+	sess := server.newSession("test", sink)
+	defer sess.close()
+
+	for _, line := range []string{initializeLine, initializedLine} {
+		var req types.Request
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			t.Fatal(err)
+		}
+		server.handleRequest(context.Background(), sess, &req)
+	}
+	sink.take()
+
+	registry := server.Registry()
+	registry.Disable("memory_info")
+	want := []string{
+		types.MethodNotificationToolsChanged,
+		types.MethodNotificationResourcesChanged,
+		types.MethodNotificationPromptsChanged,
+	}
+	if got := sink.take(); !slices.Equal(got, want) {
+		t.Errorf("after disable: %v, want %v", got, want)
+	}
+
+	// 不影响资源与提示模板的变化只通知工具列表
+	if err := registry.Register(echoTool{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sink.take(), []string{types.MethodNotificationToolsChanged}; !slices.Equal(got, want) {
+		t.Errorf("after register: %v, want %v", got, want)
+	}
+}
+
+func TestCatalogsUseSessionLocale(t *testing.T) {
+	server := NewServer()
+	sink := &recordSink{}
+	sess := server.newSession("test", sink)
+	defer sess.close()
+
+	for _, line := range []string{
+		`{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"},"_meta":{"locale":"en"}}}`,
+		initializedLine,
+	} {
+		var req types.Request
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			t.Fatal(err)
+		}
+		server.handleRequest(context.Background(), sess, &req)
+	}
+
+	call := func(method string, result any) {
+		t.Helper()
+		response := server.handleRequest(context.Background(), sess, &types.Request{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: method})
+		if response.Error != nil {
+			t.Fatalf("%s: %s", method, response.Error.Message)
+		}
+		data, _ := json.Marshal(response.Result)
+		if err := json.Unmarshal(data, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var prompts types.ListPromptsResult
+	call(types.MethodListPrompts, &prompts)
+	for _, p := range prompts.Prompts {
+		if p.Name == "diagnose_high_cpu" {
+			if want := i18n.T(i18n.English, "prompt.highCPU.description"); p.Description != want {
+				t.Errorf("prompt description = %q, want %q", p.Description, want)
+			}
+			if want := i18n.T(i18n.English, "prompt.highCPU.arg.duration"); p.Arguments[0].Description != want {
+				t.Errorf("argument description = %q, want %q", p.Arguments[0].Description, want)
+			}
+		}
+	}
+
+	var resources types.ListResourcesResult
+	call(types.MethodListResources, &resources)
+	for _, r := range resources.Resources {
+		if r.URI == "sysmon://memory" {
+			if want := i18n.T(i18n.English, "resource.memory"); r.Description != want {
+				t.Errorf("resource description = %q, want %q", r.Description, want)
+			}
+		}
+	}
+
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+	if _, err := server.resources.lookup(ctx, "sysmon://process/abc"); err == nil || err.Error() != `resource not found: invalid PID "abc"` {
+		t.Errorf("invalid pid error = %v", err)
+	}
+}
+
+func TestResourceReadTimeout(t *testing.T) {
+	registry := tools.NewRegistry()
+	if err := registry.Register(echoTool{}); err != nil {
+		t.Fatal(err)
+	}
+	// 读取函数不响应 ctx，模拟阻塞在系统调用中的采集
+	release := make(chan struct{})
+	defer close(release)
+	catalog := newResourceCatalog(registry, func(string) time.Duration { return 20 * time.Millisecond })
+	catalog.static = []staticResource{{
+		Resource: newResource("echo", "resource.system"),
+		bind: fromTool("echo", func(context.Context, echoTool, string) (any, error) {
+			<-release
+			return nil, nil
+		}),
+	}}
+	catalog.templates = nil
+
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+	want := i18n.T(i18n.English, "error.timeout", "echo", 20*time.Millisecond)
+	if _, err := catalog.read(ctx, "sysmon://echo"); err == nil || err.Error() != want {
+		t.Errorf("read = %v, want %q", err, want)
+	}
+	if _, err := sample(ctx, catalog.watch("sysmon://echo")); err == nil || err.Error() != want {
+		t.Errorf("watch = %v, want %q", err, want)
+	}
+}
+
+func TestPromptArgumentValidation(t *testing.T) {
+	// 提示模板依赖内置工具，因此使用默认的服务器
+	getPrompt := func(t *testing.T, params string) testResponse {
+		t.Helper()
+		responses := runServer(t, NewServer, initializeLine, initializedLine,
+			`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":`+params+`}`)
+		return single(t, responses[1:])
+	}
+
+	tests := []struct {
+		name   string
+		params string
+		field  string
+	}{
+		{"duration outside enum", `{"name":"diagnose_high_cpu","arguments":{"duration":"2s"}}`, "arguments.duration"},
+		{"missing mountpoint", `{"name":"investigate_disk_full","arguments":{"mountpoint":"/no/such/mountpoint"}}`, "arguments.mountpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := getPrompt(t, tt.params)
+			expectError(t, response, types.CodeInvalidParams, "1")
+			if got := response.Error.Data.Field; got != tt.field {
+				t.Errorf("error.data.field = %q, want %q", got, tt.field)
+			}
+		})
+	}
+
+	response := getPrompt(t, `{"name":"investigate_disk_full","arguments":{"mountpoint":"/"}}`)
+	if response.Error != nil {
+		t.Fatalf("prompts/get: %s", response.Error.Message)
+	}
+	var result types.GetPromptResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Messages[0].Content.Text; !strings.Contains(text, "## "+i18n.T(i18n.Default, "prompt.diskFull.target")) {
+		t.Errorf("prompt text has no mountpoint section:\n%s", text)
+	}
+}
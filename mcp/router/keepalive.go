@@ -0,0 +1,53 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"go-mcp/mcp/types"
+	"time"
+)
+
+// defaultKeepAliveTimeout 为等待客户端应答 ping 的默认时长。
+const defaultKeepAliveTimeout = 10 * time.Second
+
+// errKeepAliveTimeout 表示客户端未在规定时间内应答服务器发起的 ping。
+var errKeepAliveTimeout = errors.New("客户端未响应 ping，会话已关闭")
+
+// startKeepAlive 在后台周期性地向客户端发送 ping，直至 ctx 结束；
+// 客户端超时未应答或消息无法送达时调用一次 onTimeout 并停止。未启用保活时什么也不做。
+func (s *Server) startKeepAlive(ctx context.Context, sess *session, onTimeout func()) {
+	if s.keepAliveInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.keepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !s.ping(ctx, sess) {
+					onTimeout()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// ping 发送一次 ping 并等待应答，客户端返回任何响应（包括错误）都视为存活；
+// ctx 在等待期间结束说明会话已正常关闭，同样返回 true
+func (s *Server) ping(ctx context.Context, sess *session) bool {
+	pingCtx, cancel := context.WithTimeout(ctx, s.keepAliveTimeout)
+	defer cancel()
+
+	_, err := sess.request(pingCtx, types.MethodPing, nil)
+	if err == nil || ctx.Err() != nil {
+		return true
+	}
+	sess.local.Warn("客户端未响应 ping，关闭会话", "error", err)
+	return false
+}
@@ -0,0 +1,160 @@
+package router
+
+import (
+	"context"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/types"
+	"slices"
+)
+
+// sessionState 表示会话在 MCP 生命周期中所处的阶段。
+type sessionState int
+
+const (
+	// stateNew 尚未收到 initialize，仅允许初始化请求。
+	stateNew sessionState = iota
+	// stateInitializing 已响应 initialize，等待客户端的 notifications/initialized。
+	stateInitializing
+	// stateReady 握手完成，可以处理全部请求。
+	stateReady
+)
+
+// protocolFeatures 描述某个协议版本下可用的特性，用于按协商结果开关功能。
+type protocolFeatures struct {
+	// batch 表示是否接受 JSON-RPC 批量请求（2025-06-18 起已移除）
+	batch bool
+	// progressMessage 表示 notifications/progress 是否携带 message 字段（2025-03-26 起支持）
+	progressMessage bool
+	// structuredContent 表示工具是否可以声明 outputSchema 并返回 structuredContent（2025-06-18 起支持）
+	structuredContent bool
+	// toolAnnotations 表示 tools/list 是否携带工具注解（2025-03-26 起支持）
+	toolAnnotations bool
+	// toolTitle 表示工具定义是否携带顶层 title 字段（2025-06-18 起支持）
+	toolTitle bool
+}
+
+// featuresFor 返回协议版本对应的特性集合；版本号为日期格式，可以直接按字符串比较先后
+func featuresFor(version string) protocolFeatures {
+	return protocolFeatures{
+		batch:             version < "2025-06-18",
+		progressMessage:   version >= "2025-03-26",
+		structuredContent: version >= "2025-06-18",
+		toolAnnotations:   version >= "2025-03-26",
+		toolTitle:         version >= "2025-06-18",
+	}
+}
+
+// negotiateProtocolVersion 选择本次会话使用的协议版本：
+// 支持客户端请求的版本时原样采用，否则回退到服务器支持的最新版本，由客户端决定是否断开。
+func negotiateProtocolVersion(requested string) string {
+	if slices.Contains(types.SupportedProtocolVersions, requested) {
+		return requested
+	}
+	return types.ProtocolVersion
+}
+
+// supportedProtocolVersion 判断协议版本是否在服务器支持的范围内
+func supportedProtocolVersion(version string) bool {
+	return slices.Contains(types.SupportedProtocolVersions, version)
+}
+
+// allowedBeforeInitialize 判断方法是否可以在握手完成前调用
+func allowedBeforeInitialize(method string) bool {
+	return method == types.MethodInitialize || method == types.MethodPing
+}
+
+// beginInitialize 记录客户端信息并完成版本协商，会话已初始化过时返回 false
+func (sess *session) beginInitialize(params types.InitializeParams) (string, bool) {
+	sess.stateMu.Lock()
+	defer sess.stateMu.Unlock()
+
+	if sess.state != stateNew {
+		return "", false
+	}
+	sess.state = stateInitializing
+	sess.protocolVersion = negotiateProtocolVersion(params.ProtocolVersion)
+	sess.clientInfo = params.ClientInfo
+	sess.clientCapabilities = params.Capabilities
+	// 不支持的语言提示直接忽略，沿用服务器默认语言
+	if params.Meta != nil {
+		sess.locale, _ = i18n.Parse(params.Meta.Locale)
+	}
+	return sess.protocolVersion, true
+}
+
+// markInitialized 处理 notifications/initialized，握手完成后会话进入就绪状态
+func (sess *session) markInitialized() {
+	sess.stateMu.Lock()
+	defer sess.stateMu.Unlock()
+
+	if sess.state == stateInitializing {
+		sess.state = stateReady
+	}
+}
+
+// initialized 判断会话是否已完成 initialize 请求。为兼容不发送 initialized 通知的客户端，
+// 服务器在响应 initialize 之后即开始处理其他请求。
+func (sess *session) initialized() bool {
+	sess.stateMu.RLock()
+	defer sess.stateMu.RUnlock()
+
+	return sess.state != stateNew
+}
+
+// negotiatedVersion 返回协商得到的协议版本，尚未初始化时返回空字符串
+func (sess *session) negotiatedVersion() string {
+	sess.stateMu.RLock()
+	defer sess.stateMu.RUnlock()
+
+	return sess.protocolVersion
+}
+
+// activeVersion 返回会话实际使用的协议版本，尚未初始化时按服务器的最新版本处理
+func (sess *session) activeVersion() string {
+	if version := sess.negotiatedVersion(); version != "" {
+		return version
+	}
+	return types.ProtocolVersion
+}
+
+// features 返回会话所用协议版本下可用的特性
+func (sess *session) features() protocolFeatures {
+	return featuresFor(sess.activeVersion())
+}
+
+type featuresKey struct{}
+
+// admitRequest 在收到请求时、交给工作池之前检查握手状态。请求在工作池中异步执行，
+// 若到执行时才检查，紧随其后到达的 initialize 可能已经处理完毕，握手前的请求会被错误地受理。
+// 受理时协商的协议特性记入返回的 ctx，处理过程中通过 requestFeatures 读取。拒绝时返回错误响应。
+func (s *Server) admitRequest(ctx context.Context, sess *session, req *types.Request) (context.Context, *types.Response) {
+	if !sess.initialized() && !allowedBeforeInitialize(req.Method) {
+		return ctx, s.notInitializedResponse(req)
+	}
+	return context.WithValue(ctx, featuresKey{}, sess.features()), nil
+}
+
+// requestFeatures 返回受理请求时的协议特性，ctx 未经 admitRequest 时取会话当前的特性
+func requestFeatures(ctx context.Context, sess *session) protocolFeatures {
+	if features, ok := ctx.Value(featuresKey{}).(protocolFeatures); ok {
+		return features
+	}
+	return sess.features()
+}
+
+// notInitializedResponse 拒绝握手完成前收到的请求
+func (s *Server) notInitializedResponse(req *types.Request) *types.Response {
+	return s.errorResponseFor(req, types.NewError(types.CodeInvalidRequest, "Invalid Request: server not initialized").
+		WithData(types.ErrorData{Method: req.Method, Expected: types.MethodInitialize}))
+}
+
+// outputLocale 返回会话的输出语言，客户端未声明时使用 fallback
+func (sess *session) outputLocale(fallback i18n.Locale) i18n.Locale {
+	sess.stateMu.RLock()
+	defer sess.stateMu.RUnlock()
+
+	if sess.locale != "" {
+		return sess.locale
+	}
+	return fallback
+}
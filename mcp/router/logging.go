@@ -0,0 +1,185 @@
+package router
+
+import (
+	"context"
+	"go-mcp/mcp/types"
+	"log/slog"
+	"time"
+)
+
+// slog 没有的 MCP 日志级别，按 slog 的惯例以 4 为间隔排列在 Info 与 Error 之上
+const (
+	levelNotice    = slog.LevelInfo + 2
+	levelCritical  = slog.LevelError + 4
+	levelAlert     = slog.LevelError + 8
+	levelEmergency = slog.LevelError + 12
+)
+
+// loggingLevels 将 MCP 日志级别映射为 slog 级别
+var loggingLevels = map[types.LoggingLevel]slog.Level{
+	types.LoggingLevelDebug:     slog.LevelDebug,
+	types.LoggingLevelInfo:      slog.LevelInfo,
+	types.LoggingLevelNotice:    levelNotice,
+	types.LoggingLevelWarning:   slog.LevelWarn,
+	types.LoggingLevelError:     slog.LevelError,
+	types.LoggingLevelCritical:  levelCritical,
+	types.LoggingLevelAlert:     levelAlert,
+	types.LoggingLevelEmergency: levelEmergency,
+}
+
+// loggingLevel 将 slog 级别转换为不高于它的最严重的 MCP 日志级别
+func loggingLevel(level slog.Level) types.LoggingLevel {
+	switch {
+	case level >= levelEmergency:
+		return types.LoggingLevelEmergency
+	case level >= levelAlert:
+		return types.LoggingLevelAlert
+	case level >= levelCritical:
+		return types.LoggingLevelCritical
+	case level >= slog.LevelError:
+		return types.LoggingLevelError
+	case level >= slog.LevelWarn:
+		return types.LoggingLevelWarning
+	case level >= levelNotice:
+		return types.LoggingLevelNotice
+	case level >= slog.LevelInfo:
+		return types.LoggingLevelInfo
+	default:
+		return types.LoggingLevelDebug
+	}
+}
+
+// setLogLevel 记录客户端通过 logging/setLevel 设置的最低日志级别
+func (sess *session) setLogLevel(level slog.Level) {
+	sess.stateMu.Lock()
+	defer sess.stateMu.Unlock()
+
+	sess.logLevel = level
+	sess.logToClient = true
+}
+
+// logsToClient 判断指定级别的日志是否需要推送给客户端；客户端未调用 logging/setLevel 时不推送
+func (sess *session) logsToClient(level slog.Level) bool {
+	sess.stateMu.RLock()
+	defer sess.stateMu.RUnlock()
+
+	return sess.logToClient && level >= sess.logLevel
+}
+
+// clientLogHandler 将日志写入服务器日志，同时把达到客户端所设级别的记录
+// 以 notifications/message 推送给会话，使客户端能看到服务器端的诊断信息。
+type clientLogHandler struct {
+	base slog.Handler
+	sess *session
+	// name 为通知中的 logger 字段
+	name string
+
+	// attrs 为 With 附加的属性，键已带上所属分组的前缀；group 为后续属性的键前缀
+	attrs []slog.Attr
+	group string
+}
+
+// newClientLogger 创建同时写入 base 并推送给 sess 的日志记录器
+func newClientLogger(base *slog.Logger, sess *session, name string) *slog.Logger {
+	return slog.New(&clientLogHandler{base: base.Handler(), sess: sess, name: name})
+}
+
+func (h *clientLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level) || h.sess.logsToClient(level)
+}
+
+func (h *clientLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	var err error
+	if h.base.Enabled(ctx, record.Level) {
+		err = h.base.Handle(ctx, record)
+	}
+
+	if h.sess.logsToClient(record.Level) {
+		data := map[string]any{"message": record.Message}
+		for _, attr := range h.attrs {
+			addLogAttr(data, "", attr)
+		}
+		record.Attrs(func(attr slog.Attr) bool {
+			addLogAttr(data, h.group, attr)
+			return true
+		})
+
+		// 请求处理期间的日志经由该请求的消息出口发出，与响应位于同一个流中
+		h.sess.notifyRequest(ctx, types.MethodNotificationMessage, types.LoggingMessageParams{
+			Level:  loggingLevel(record.Level),
+			Logger: h.name,
+			Data:   data,
+		})
+	}
+	return err
+}
+
+func (h *clientLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.base = h.base.WithAttrs(attrs)
+	clone.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	clone.attrs = append(clone.attrs, h.attrs...)
+	for _, attr := range attrs {
+		attr.Key = h.group + attr.Key
+		clone.attrs = append(clone.attrs, attr)
+	}
+	return &clone
+}
+
+func (h *clientLogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.base = h.base.WithGroup(name)
+	clone.group = h.group + name + "."
+	return &clone
+}
+
+// addLogAttr 将属性展开写入通知的 data 对象，分组以 "." 连接键名
+func addLogAttr(data map[string]any, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		// 键为空的分组直接内联
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range value.Group() {
+			addLogAttr(data, prefix, member)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+
+	switch v := value.Any().(type) {
+	case error:
+		// error 直接编码为 JSON 时是空对象
+		data[prefix+attr.Key] = v.Error()
+	case time.Duration:
+		data[prefix+attr.Key] = v.String()
+	default:
+		data[prefix+attr.Key] = v
+	}
+}
+
+// handleSetLevel 处理 logging/setLevel 请求，设置推送给客户端的最低日志级别
+func (s *Server) handleSetLevel(sess *session, req *types.Request) *types.Response {
+	var params types.SetLevelParams
+	if errResp := s.decodeParams(req, &params); errResp != nil {
+		return errResp
+	}
+	if params.Level == "" {
+		return s.missingParamResponse(req, "level")
+	}
+
+	level, ok := loggingLevels[params.Level]
+	if !ok {
+		return s.errorResponseFor(req, types.NewErrorf(types.CodeInvalidParams, "Invalid params: unknown logging level %q", params.Level).
+			WithData(types.ErrorData{Field: "level", Method: req.Method}))
+	}
+	sess.setLogLevel(level)
+
+	return s.resultResponse(req, struct{}{})
+}
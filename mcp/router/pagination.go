@@ -0,0 +1,53 @@
+package router
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+)
+
+const (
+	// defaultPageSize 为列表类请求每页返回的默认条目数。
+	defaultPageSize = 50
+	// cursorPrefix 标记由本服务器签发的游标，同时作为游标格式的版本号。
+	cursorPrefix = "v1:"
+)
+
+// errInvalidCursor 表示客户端提供的分页游标无法识别。
+var errInvalidCursor = errors.New("invalid cursor")
+
+// paginate 对按名称排序后的条目分页，返回本页的名称与下一页的游标（没有下一页时为空）。
+// 游标编码本页最后一个名称而不是偏移量，列表在两次请求之间增删条目时也不会重复或遗漏；
+// 不带 cursorPrefix 的游标不是本服务器签发的，返回 errInvalidCursor。
+func paginate(names []string, cursor string, pageSize int) ([]string, string, error) {
+	sort.Strings(names)
+
+	start := 0
+	if cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, "", errInvalidCursor
+		}
+		after, ok := strings.CutPrefix(string(decoded), cursorPrefix)
+		if !ok || after == "" {
+			return nil, "", errInvalidCursor
+		}
+		start = sort.SearchStrings(names, after)
+		if start < len(names) && names[start] == after {
+			start++
+		}
+	}
+
+	end := len(names)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+
+	page := names[start:end]
+	next := ""
+	if end < len(names) {
+		next = base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + page[len(page)-1]))
+	}
+	return page, next, nil
+}
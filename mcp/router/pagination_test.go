@@ -0,0 +1,53 @@
+package router
+
+import (
+	"encoding/base64"
+	"errors"
+	"go-mcp/mcp/types"
+	"slices"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	names := []string{"e", "c", "a", "d", "b"}
+	cursor := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+		page   []string
+		next   string
+		err    error
+	}{
+		{"first page", "", []string{"a", "b"}, cursor("v1:b"), nil},
+		{"middle page", cursor("v1:b"), []string{"c", "d"}, cursor("v1:d"), nil},
+		{"last page", cursor("v1:d"), []string{"e"}, "", nil},
+		{"removed entry", cursor("v1:bb"), []string{"c", "d"}, cursor("v1:d"), nil},
+		{"garbage", "zzz", nil, "", errInvalidCursor},
+		{"not base64", "!!", nil, "", errInvalidCursor},
+		{"bare name", cursor("b"), nil, "", errInvalidCursor},
+		{"empty name", cursor("v1:"), nil, "", errInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, next, err := paginate(slices.Clone(names), tt.cursor, 2)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if !slices.Equal(page, tt.page) {
+				t.Errorf("page = %v, want %v", page, tt.page)
+			}
+			if next != tt.next {
+				t.Errorf("next = %q, want %q", next, tt.next)
+			}
+		})
+	}
+}
+
+func TestListToolsRejectsForeignCursor(t *testing.T) {
+	response := single(t, runInitialized(t, `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"cursor":"zzz"}}`))
+	expectError(t, response, types.CodeInvalidParams, "1")
+	if got := response.Error.Data.Field; got != "cursor" {
+		t.Errorf("error.data.field = %q, want cursor", got)
+	}
+}
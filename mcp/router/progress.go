@@ -0,0 +1,65 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"go-mcp/mcp/types"
+	"sync"
+)
+
+// progressNotifier 将工具汇报的进度转换为 notifications/progress 发往客户端。
+type progressNotifier struct {
+	ctx   context.Context
+	sess  *session
+	token json.RawMessage
+
+	mu   sync.Mutex
+	last float64
+	sent bool
+}
+
+// newProgressNotifier 为携带 progressToken 的请求创建进度通知器
+func newProgressNotifier(ctx context.Context, sess *session, token json.RawMessage) *progressNotifier {
+	return &progressNotifier{
+		ctx:   ctx,
+		sess:  sess,
+		token: token,
+	}
+}
+
+// Report 发送进度通知；按 MCP 规范进度必须递增，因此不大于上次的进度会被忽略
+func (p *progressNotifier) Report(progress, total float64, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx.Err() != nil || (p.sent && progress <= p.last) {
+		return
+	}
+	p.last, p.sent = progress, true
+
+	// 2025-03-26 之前的协议版本没有 message 字段
+	if !requestFeatures(p.ctx, p.sess).progressMessage {
+		message = ""
+	}
+
+	p.sess.notifyRequest(p.ctx, types.MethodNotificationProgress, types.ProgressParams{
+		ProgressToken: p.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
+
+type requestSinkKey struct{}
+
+// withRequestSink 为请求指定专用的消息出口，与该请求相关的通知将经由它发出
+// （如 Streamable HTTP 中以 SSE 形式返回的 POST 响应流）。
+func withRequestSink(ctx context.Context, sink messageSink) context.Context {
+	return context.WithValue(ctx, requestSinkKey{}, sink)
+}
+
+// requestSinkFrom 取出请求专用的消息出口，未设置时返回 nil
+func requestSinkFrom(ctx context.Context) messageSink {
+	sink, _ := ctx.Value(requestSinkKey{}).(messageSink)
+	return sink
+}
@@ -0,0 +1,258 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/tools"
+	"go-mcp/mcp/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errPromptNotFound 表示请求的提示模板不存在。
+var errPromptNotFound = errors.New("prompt not found")
+
+// toolRunner 以给定参数执行已注册的工具并返回其文本输出。
+type toolRunner func(ctx context.Context, name string, args map[string]interface{}) (string, error)
+
+// promptDefinition 绑定提示模板的描述及其渲染函数。Prompt 中模板与参数的描述均为 i18n 消息键，
+// 经 localized 按会话语言翻译后返回给客户端。
+type promptDefinition struct {
+	types.Prompt
+	// tools 为渲染时需要的工具，其中任一工具被停用或注销时模板不可用
+	tools []string
+	build func(ctx context.Context, run toolRunner, args map[string]string) (string, error)
+}
+
+// promptCatalog 汇总服务器内置的诊断提示模板，模板中的实时数据来自注册表中的工具。
+// 不经 toolRunner 直接读取工具数据时同样施加该工具的执行时限。
+type promptCatalog struct {
+	registry *tools.Registry
+	timeout  func(name string) time.Duration
+	prompts  []promptDefinition
+}
+
+// newPromptCatalog 构建内置的故障排查提示模板，timeout 返回各工具的执行时限。
+func newPromptCatalog(registry *tools.Registry, timeout func(name string) time.Duration) *promptCatalog {
+	return &promptCatalog{
+		registry: registry,
+		timeout:  timeout,
+		prompts: []promptDefinition{
+			{
+				Prompt: types.Prompt{
+					Name:        "diagnose_high_cpu",
+					Description: "prompt.highCPU.description",
+					Arguments: []types.PromptArgument{
+						{Name: "duration", Description: "prompt.highCPU.arg.duration"},
+					},
+				},
+				tools: []string{"cpu_info", "top_processes"},
+				build: func(ctx context.Context, run toolRunner, args map[string]string) (string, error) {
+					cpuTool, ok := registry.Lookup("cpu_info")
+					if !ok {
+						return "", errPromptNotFound
+					}
+					return buildHighCPUPrompt(ctx, cpuTool, run, args)
+				},
+			},
+			{
+				Prompt: types.Prompt{
+					Name:        "investigate_disk_full",
+					Description: "prompt.diskFull.description",
+					Arguments: []types.PromptArgument{
+						{Name: "mountpoint", Description: "prompt.diskFull.arg.mountpoint", Required: true},
+					},
+				},
+				tools: []string{"disk_info"},
+				build: func(ctx context.Context, run toolRunner, args map[string]string) (string, error) {
+					diskTool, ok := lookupTool[*tools.DiskTool](registry, "disk_info")
+					if !ok {
+						return "", errPromptNotFound
+					}
+					return buildDiskFullPrompt(ctx, diskTool, timeout("disk_info"), run, args)
+				},
+			},
+			{
+				Prompt: types.Prompt{
+					Name:        "diagnose_slow_process",
+					Description: "prompt.slowProcess.description",
+					Arguments: []types.PromptArgument{
+						{Name: "pid", Description: "prompt.slowProcess.arg.pid", Required: true},
+					},
+				},
+				tools: []string{"top_processes", "cpu_info", "memory_info"},
+				build: func(ctx context.Context, run toolRunner, args map[string]string) (string, error) {
+					processTool, ok := lookupTool[*tools.ProcessTool](registry, "top_processes")
+					if !ok {
+						return "", errPromptNotFound
+					}
+					return buildSlowProcessPrompt(ctx, processTool, timeout("top_processes"), run, args)
+				},
+			},
+		},
+	}
+}
+
+// available 判断提示模板需要的工具是否均已启用
+func (c *promptCatalog) available(p promptDefinition) bool {
+	for _, name := range p.tools {
+		if _, ok := c.registry.Lookup(name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// localized 返回以指定语言描述的提示模板
+func (p promptDefinition) localized(locale i18n.Locale) types.Prompt {
+	prompt := p.Prompt
+	prompt.Description = i18n.T(locale, p.Description)
+	prompt.Arguments = make([]types.PromptArgument, len(p.Arguments))
+	for i, arg := range p.Arguments {
+		arg.Description = i18n.T(locale, arg.Description)
+		prompt.Arguments[i] = arg
+	}
+	return prompt
+}
+
+// list 返回当前可用的提示模板的描述。
+func (c *promptCatalog) list(locale i18n.Locale) []types.Prompt {
+	list := make([]types.Prompt, 0, len(c.prompts))
+	for _, p := range c.prompts {
+		if c.available(p) {
+			list = append(list, p.localized(locale))
+		}
+	}
+	return list
+}
+
+// signature 返回当前可用的提示模板的标识，用于判断列表是否因注册表变化而改变
+func (c *promptCatalog) signature() string {
+	var b strings.Builder
+	for _, p := range c.list(i18n.Default) {
+		b.WriteString(p.Name + "\n")
+	}
+	return b.String()
+}
+
+// get 校验参数并渲染指定的提示模板；依赖的工具不可用的模板视为不存在。
+func (c *promptCatalog) get(ctx context.Context, name string, args map[string]string, run toolRunner) (types.GetPromptResult, error) {
+	for _, p := range c.prompts {
+		if p.Name != name {
+			continue
+		}
+		if !c.available(p) {
+			return types.GetPromptResult{}, errPromptNotFound
+		}
+
+		for _, arg := range p.Arguments {
+			if arg.Required && strings.TrimSpace(args[arg.Name]) == "" {
+				return types.GetPromptResult{}, fmt.Errorf("missing required argument: %s", arg.Name)
+			}
+		}
+
+		text, err := p.build(ctx, run, args)
+		if err != nil {
+			return types.GetPromptResult{}, err
+		}
+
+		return types.GetPromptResult{
+			Description: i18n.T(i18n.FromContext(ctx), p.Description),
+			Messages: []types.PromptMessage{
+				{Role: "user", Content: types.ContentItem{Type: "text", Text: text}},
+			},
+		}, nil
+	}
+
+	return types.GetPromptResult{}, errPromptNotFound
+}
+
+// buildHighCPUPrompt 渲染 CPU 使用率过高的诊断提示，duration 按 cpu_info 工具的输入模式校验。
+func buildHighCPUPrompt(ctx context.Context, cpuTool types.MonitorTool, run toolRunner, args map[string]string) (string, error) {
+	duration := strings.TrimSpace(args["duration"])
+	if duration == "" {
+		duration = "1s"
+	}
+	if err := cpuTool.GetInputSchema(i18n.Default).Validate(map[string]interface{}{"duration": duration}); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(i18n.FromContext(ctx), "prompt.highCPU.text"))
+	writeToolSection(ctx, &b, run, "cpu_info", map[string]interface{}{"duration": duration})
+	writeToolSection(ctx, &b, run, "top_processes", map[string]interface{}{"sort_by": "cpu", "limit": 10})
+
+	return b.String(), nil
+}
+
+// buildDiskFullPrompt 渲染磁盘空间不足的排查提示，附带所请求挂载点的使用情况；挂载点不存在时返回错误。
+func buildDiskFullPrompt(ctx context.Context, dt *tools.DiskTool, timeout time.Duration, run toolRunner, args map[string]string) (string, error) {
+	mountpoint := strings.TrimSpace(args["mountpoint"])
+	data, err := runWithTimeout(ctx, "disk_info", timeout, func(ctx context.Context) (any, error) {
+		return readDiskPartition(ctx, dt, mountpoint)
+	})
+	if err != nil {
+		return "", &types.ArgumentError{Field: "mountpoint", Reason: err.Error()}
+	}
+	partition := data.(types.DiskPartition)
+
+	locale := i18n.FromContext(ctx)
+
+	var b strings.Builder
+	b.WriteString(i18n.T(locale, "prompt.diskFull.text", mountpoint))
+
+	const gb = 1024 * 1024 * 1024
+	fmt.Fprintf(&b, "## %s\n", i18n.T(locale, "prompt.diskFull.target"))
+	fmt.Fprintf(&b, "%s\n\n", i18n.T(locale, "prompt.diskFull.details",
+		partition.Mountpoint, partition.Device, partition.Fstype,
+		float64(partition.Total)/gb, float64(partition.Used)/gb, partition.UsedPercent, float64(partition.Free)/gb))
+
+	writeToolSection(ctx, &b, run, "disk_info", map[string]interface{}{"show_all": true})
+
+	return b.String(), nil
+}
+
+// buildSlowProcessPrompt 渲染进程运行缓慢的分析提示，timeout 为读取目标进程信息的时限。
+func buildSlowProcessPrompt(ctx context.Context, pt *tools.ProcessTool, timeout time.Duration, run toolRunner, args map[string]string) (string, error) {
+	pid, err := strconv.ParseInt(strings.TrimSpace(args["pid"]), 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid pid: %s", args["pid"])
+	}
+
+	locale := i18n.FromContext(ctx)
+
+	var b strings.Builder
+	b.WriteString(i18n.T(locale, "prompt.slowProcess.text", pid))
+
+	fmt.Fprintf(&b, "## %s\n", i18n.T(locale, "prompt.slowProcess.target"))
+	procInfo, err := runWithTimeout(ctx, "top_processes", timeout, func(ctx context.Context) (types.ProcessInfo, error) {
+		return pt.GetProcessByPID(ctx, int32(pid))
+	})
+	if err != nil {
+		fmt.Fprintf(&b, "%s\n\n", i18n.T(locale, "prompt.fetchFailed", err))
+	} else {
+		fmt.Fprintf(&b, "%s\n\n", i18n.T(locale, "prompt.slowProcess.details",
+			procInfo.PID, procInfo.Name, procInfo.Status, procInfo.CPUPercent, procInfo.MemoryMB))
+	}
+
+	writeToolSection(ctx, &b, run, "cpu_info", map[string]interface{}{"duration": "1s"})
+	writeToolSection(ctx, &b, run, "memory_info", nil)
+	writeToolSection(ctx, &b, run, "top_processes", map[string]interface{}{"sort_by": "cpu", "limit": 10})
+
+	return b.String(), nil
+}
+
+// writeToolSection 执行工具并将输出追加为提示中的一节，失败时写入错误信息以便模型知晓数据缺失。
+func writeToolSection(ctx context.Context, b *strings.Builder, run toolRunner, name string, args map[string]interface{}) {
+	fmt.Fprintf(b, "## %s\n", name)
+	output, err := run(ctx, name, args)
+	if err != nil {
+		fmt.Fprintf(b, "%s\n\n", i18n.T(i18n.FromContext(ctx), "prompt.fetchFailed", err))
+		return
+	}
+	b.WriteString(output)
+	b.WriteString("\n")
+}
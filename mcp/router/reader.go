@@ -0,0 +1,85 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go-mcp/mcp/types"
+	"io"
+	"net/http"
+)
+
+// defaultMaxMessageSize 为单条 JSON-RPC 消息的默认大小上限。
+const defaultMaxMessageSize = 4 << 20
+
+// readBufferSize 为读取输入流时每次向底层读取的字节数，与消息大小上限无关。
+const readBufferSize = 64 << 10
+
+// errMessageTooLarge 表示单条消息超过了大小上限。
+var errMessageTooLarge = errors.New("message too large")
+
+// messageReader 从输入流中逐条读取以换行分隔的 JSON-RPC 消息。
+// 与 bufio.Scanner 不同，超过上限的消息不会中断读取：其余部分边读边丢弃而不缓存，
+// 调用方回复错误后即可继续读取下一条消息。
+type messageReader struct {
+	r     *bufio.Reader
+	limit int64
+}
+
+// newMessageReader 创建消息读取器，limit 为单条消息（不含换行符）的字节数上限
+func newMessageReader(r io.Reader, limit int64) *messageReader {
+	return &messageReader{r: bufio.NewReaderSize(r, readBufferSize), limit: limit}
+}
+
+// next 返回下一条消息，不含结尾的换行符；消息超过上限时返回 errMessageTooLarge，
+// 输入流结束时返回 io.EOF。返回的切片归调用方所有。
+func (mr *messageReader) next() ([]byte, error) {
+	var message []byte
+	oversized := false
+
+	for {
+		chunk, err := mr.r.ReadSlice('\n')
+		if !oversized {
+			message = append(message, chunk...)
+			if int64(len(bytes.TrimRight(message, "\r\n"))) > mr.limit {
+				oversized = true
+				message = nil
+			}
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// 一行超过缓冲区大小，继续读取同一条消息
+			continue
+		}
+		if errors.Is(err, io.EOF) && (len(message) > 0 || oversized) {
+			// 最后一条消息可以没有结尾的换行符
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	if oversized {
+		return nil, errMessageTooLarge
+	}
+	return bytes.TrimRight(message, "\r\n"), nil
+}
+
+// readBody 读取 HTTP 请求体，超过 limit 时返回 errMessageTooLarge
+func readBody(w http.ResponseWriter, r *http.Request, limit int64) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return nil, errMessageTooLarge
+	}
+	return body, err
+}
+
+// messageTooLargeResponse 创建消息超过大小上限时的错误响应；消息未被解析，无法得知请求 ID
+func (s *Server) messageTooLargeResponse() *types.Response {
+	return invalidMessage(nullID, types.CodeInvalidRequest,
+		fmt.Sprintf("Invalid Request: message exceeds the %d byte limit", s.maxMessageSize), types.ErrorData{})
+}
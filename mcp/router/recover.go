@@ -0,0 +1,114 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/types"
+	"runtime/debug"
+	"time"
+)
+
+// defaultToolTimeout 为单次工具调用的默认执行时限。
+const defaultToolTimeout = 30 * time.Second
+
+// errToolTimeout 作为工具超过执行时限时的 context 取消原因。
+var errToolTimeout = errors.New("tool execution timed out")
+
+// panicError 记录在其他 goroutine 中捕获的 panic 及当时的调用栈，
+// 以便在处理请求的 goroutine 中重新抛出后仍能记录原始位置。
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+// recoverResponse 记录处理请求时发生的 panic，并为请求创建内部错误响应；通知无需响应，返回 nil
+func (s *Server) recoverResponse(sess *session, req *types.Request, value any) *types.Response {
+	stack := debug.Stack()
+	if p, ok := value.(*panicError); ok {
+		value, stack = p.value, p.stack
+	}
+	sess.local.Error("处理请求时发生 panic", "method", req.Method, "panic", value, "stack", string(stack))
+
+	if req.ID == nil {
+		return nil
+	}
+	return s.errorResponseFor(req, types.NewError(types.CodeInternalError, "Internal error: request handler panicked").
+		WithData(types.ErrorData{Method: req.Method, Detail: fmt.Sprint(value)}))
+}
+
+// toolTimeout 返回指定工具的执行时限，不大于 0 表示不限时
+func (s *Server) toolTimeout(name string) time.Duration {
+	if timeout, ok := s.toolTimeouts[name]; ok {
+		return timeout
+	}
+	return s.defaultToolTimeout
+}
+
+// callTool 以工具的执行时限执行工具，见 runWithTimeout。
+func (s *Server) callTool(ctx context.Context, tool types.MonitorTool, args map[string]interface{}) (types.ToolOutput, error) {
+	return runWithTimeout(ctx, tool.GetName(), s.toolTimeout(tool.GetName()), func(ctx context.Context) (types.ToolOutput, error) {
+		return tool.Execute(ctx, args)
+	})
+}
+
+// runWithTimeout 在独立的 goroutine 中执行名为 name 的工具的一次调用并施加执行时限，
+// 工具调用与读取基于工具的资源共用这一实现。gopsutil 的部分调用（如卡住的 NFS
+// 挂载点上的 disk.Usage）阻塞在系统调用中，不响应 ctx，因此超时后不再等待工具返回，
+// 直接返回超时错误，由工具 goroutine 在后台自行结束。工具内的 panic 会在调用方的 goroutine
+// 中重新抛出，交由请求处理或采样的 recover 转换为错误。
+func runWithTimeout[T any](ctx context.Context, name string, timeout time.Duration, run func(ctx context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, errToolTimeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		value T
+		err   error
+		panic *panicError
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				done <- outcome{panic: &panicError{value: value, stack: debug.Stack()}}
+			}
+		}()
+		value, err := run(ctx)
+		done <- outcome{value: value, err: err}
+	}()
+
+	var result outcome
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result.err = context.Cause(ctx)
+	}
+
+	if result.panic != nil {
+		panic(result.panic)
+	}
+	// 响应 ctx 的工具超时后通常返回 context.DeadlineExceeded，统一转换为说明超时的错误
+	if result.err != nil && errors.Is(context.Cause(ctx), errToolTimeout) {
+		var zero T
+		return zero, i18n.Errorf(i18n.FromContext(ctx), "error.timeout", name, timeout)
+	}
+	return result.value, result.err
+}
+
+// sample 读取一次资源数据，读取过程中的 panic 转换为错误，避免后台采样器使整个服务器退出
+func sample(ctx context.Context, read func(ctx context.Context) (any, error)) (data any, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &panicError{value: value, stack: debug.Stack()}
+		}
+	}()
+	return read(ctx)
+}
@@ -0,0 +1,330 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/tools"
+	"go-mcp/mcp/types"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// resourceScheme 是所有监控资源 URI 的统一前缀。
+	resourceScheme = "sysmon://"
+	// resourceMimeType 资源内容均为 types.*Info 结构的 JSON 编码。
+	resourceMimeType = "application/json"
+	// resourceCPUSample 读取 CPU 资源时的采样时长。
+	resourceCPUSample = time.Second
+	// resourceProcessLimit 进程列表资源返回的进程数量。
+	resourceProcessLimit = 10
+)
+
+// errResourceNotFound 表示请求的 URI 不对应任何已知资源。
+var errResourceNotFound = errors.New("resource not found")
+
+// resourceReader 读取资源数据，arg 为资源模板中的参数，静态资源为空。
+type resourceReader func(ctx context.Context, arg string) (any, error)
+
+// resourceBinding 从目录的注册表中取出提供资源数据的工具并绑定读取函数，工具不可用时返回 false。
+type resourceBinding func(c *resourceCatalog) (resourceReader, bool)
+
+// staticResource 绑定一个固定 URI 的资源及其数据来源。
+type staticResource struct {
+	types.Resource
+	bind resourceBinding
+}
+
+// templateResource 绑定一类参数化资源，prefix 为 URI 中参数之前的固定部分。
+type templateResource struct {
+	types.ResourceTemplate
+	prefix string
+	bind   resourceBinding
+}
+
+// resourceCatalog 汇总服务器暴露的全部监控资源。资源数据来自注册表中的工具，
+// 工具被停用或注销后，依赖它的资源不再出现在列表中，也无法读取或订阅。
+// 读取资源与调用工具一样受该工具的执行时限约束。
+type resourceCatalog struct {
+	registry  *tools.Registry
+	timeout   func(name string) time.Duration
+	static    []staticResource
+	templates []templateResource
+}
+
+// lookupTool 从注册表中取出指定名称与类型的已启用工具；同名工具被替换为其他实现时返回 false
+func lookupTool[T types.MonitorTool](registry *tools.Registry, name string) (T, bool) {
+	var zero T
+	tool, ok := registry.Lookup(name)
+	if !ok {
+		return zero, false
+	}
+	typed, ok := tool.(T)
+	return typed, ok
+}
+
+// fromTool 创建以注册表中名为 name 的工具读取数据的资源绑定，读取时施加该工具的执行时限
+func fromTool[T types.MonitorTool](name string, read func(ctx context.Context, tool T, arg string) (any, error)) resourceBinding {
+	return func(c *resourceCatalog) (resourceReader, bool) {
+		tool, ok := lookupTool[T](c.registry, name)
+		if !ok {
+			return nil, false
+		}
+		return func(ctx context.Context, arg string) (any, error) {
+			return runWithTimeout(ctx, name, c.timeout(name), func(ctx context.Context) (any, error) {
+				return read(ctx, tool, arg)
+			})
+		}, true
+	}
+}
+
+// newResourceCatalog 基于注册表中各监控工具的数据接口构建资源目录，timeout 返回各工具的执行时限。
+func newResourceCatalog(registry *tools.Registry, timeout func(name string) time.Duration) *resourceCatalog {
+	return &resourceCatalog{
+		registry: registry,
+		timeout:  timeout,
+		static: []staticResource{
+			{
+				Resource: newResource("system", "resource.system"),
+				bind: fromTool("system_overview", func(ctx context.Context, st *tools.SystemTool, _ string) (any, error) {
+					return st.GetSystemData(ctx, false)
+				}),
+			},
+			{
+				Resource: newResource("cpu", "resource.cpu"),
+				bind: fromTool("cpu_info", func(ctx context.Context, ct *tools.CPUTool, _ string) (any, error) {
+					return ct.GetCPUData(ctx, resourceCPUSample)
+				}),
+			},
+			{
+				Resource: newResource("memory", "resource.memory"),
+				bind: fromTool("memory_info", func(ctx context.Context, mt *tools.MemoryTool, _ string) (any, error) {
+					return mt.GetMemoryData(ctx)
+				}),
+			},
+			{
+				Resource: newResource("disk", "resource.disk"),
+				bind: fromTool("disk_info", func(ctx context.Context, dt *tools.DiskTool, _ string) (any, error) {
+					return dt.GetDiskData(ctx, false)
+				}),
+			},
+			{
+				Resource: newResource("network", "resource.network"),
+				bind: fromTool("network_stats", func(ctx context.Context, nt *tools.NetworkTool, _ string) (any, error) {
+					return nt.GetNetworkData(ctx, false, "")
+				}),
+			},
+			{
+				Resource: newResource("processes", "resource.processes"),
+				bind: fromTool("top_processes", func(ctx context.Context, pt *tools.ProcessTool, _ string) (any, error) {
+					return pt.GetProcessData(ctx, "memory", resourceProcessLimit)
+				}),
+			},
+		},
+		templates: []templateResource{
+			{
+				ResourceTemplate: newResourceTemplate("disk", "mountpoint", "resource.diskByMountpoint"),
+				prefix:           resourceScheme + "disk/",
+				bind:             fromTool("disk_info", readDiskPartition),
+			},
+			{
+				ResourceTemplate: newResourceTemplate("network", "interface", "resource.networkByInterface"),
+				prefix:           resourceScheme + "network/",
+				bind:             fromTool("network_stats", readNetworkInterface),
+			},
+			{
+				ResourceTemplate: newResourceTemplate("process", "pid", "resource.processByPID"),
+				prefix:           resourceScheme + "process/",
+				bind: fromTool("top_processes", func(ctx context.Context, pt *tools.ProcessTool, arg string) (any, error) {
+					pid, err := strconv.ParseInt(arg, 10, 32)
+					if err != nil {
+						return nil, i18n.Errorf(i18n.FromContext(ctx), "resource.error.pid", errResourceNotFound, arg)
+					}
+					return pt.GetProcessByPID(ctx, int32(pid))
+				}),
+			},
+		},
+	}
+}
+
+// newResource 构造一个 sysmon:// 静态资源描述，description 为 i18n 消息键，列出资源时按会话语言翻译。
+func newResource(name, description string) types.Resource {
+	return types.Resource{
+		URI:         resourceScheme + name,
+		Name:        name,
+		Description: description,
+		MimeType:    resourceMimeType,
+	}
+}
+
+// newResourceTemplate 构造形如 sysmon://name/{param} 的资源模板描述，description 同样为 i18n 消息键。
+func newResourceTemplate(name, param, description string) types.ResourceTemplate {
+	return types.ResourceTemplate{
+		URITemplate: resourceScheme + name + "/{" + param + "}",
+		Name:        name + "_by_" + param,
+		Description: description,
+		MimeType:    resourceMimeType,
+	}
+}
+
+// resources 返回数据来源工具可用的静态资源，描述使用指定语言。
+func (c *resourceCatalog) resources(locale i18n.Locale) []types.Resource {
+	list := make([]types.Resource, 0, len(c.static))
+	for _, r := range c.static {
+		if _, ok := r.bind(c); ok {
+			resource := r.Resource
+			resource.Description = i18n.T(locale, resource.Description)
+			list = append(list, resource)
+		}
+	}
+	return list
+}
+
+// resourceTemplates 返回数据来源工具可用的资源模板，描述使用指定语言。
+func (c *resourceCatalog) resourceTemplates(locale i18n.Locale) []types.ResourceTemplate {
+	list := make([]types.ResourceTemplate, 0, len(c.templates))
+	for _, t := range c.templates {
+		if _, ok := t.bind(c); ok {
+			template := t.ResourceTemplate
+			template.Description = i18n.T(locale, template.Description)
+			list = append(list, template)
+		}
+	}
+	return list
+}
+
+// signature 返回当前可见的资源与资源模板的标识，用于判断列表是否因注册表变化而改变
+func (c *resourceCatalog) signature() string {
+	var b strings.Builder
+	for _, r := range c.resources(i18n.Default) {
+		b.WriteString(r.URI + "\n")
+	}
+	for _, t := range c.resourceTemplates(i18n.Default) {
+		b.WriteString(t.URITemplate + "\n")
+	}
+	return b.String()
+}
+
+// read 读取 URI 对应的资源，并将其编码为 JSON 文本内容。
+func (c *resourceCatalog) read(ctx context.Context, uri string) (types.ResourceContents, error) {
+	data, err := c.lookup(ctx, uri)
+	if err != nil {
+		return types.ResourceContents{}, err
+	}
+
+	text, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return types.ResourceContents{}, i18n.Errorf(i18n.FromContext(ctx), "resource.error.encode", uri, err)
+	}
+
+	return types.ResourceContents{
+		URI:      uri,
+		MimeType: resourceMimeType,
+		Text:     string(text),
+	}, nil
+}
+
+// lookup 根据 URI 找到资源并读取原始数据。
+func (c *resourceCatalog) lookup(ctx context.Context, uri string) (any, error) {
+	read, err := c.resolve(uri)
+	if err != nil {
+		return nil, err
+	}
+	return read(ctx)
+}
+
+// resolve 根据 URI 找到资源的读取函数，但不立即读取；数据来源工具不可用的资源视为不存在。
+func (c *resourceCatalog) resolve(uri string) (func(ctx context.Context) (any, error), error) {
+	for _, r := range c.static {
+		if r.URI != uri {
+			continue
+		}
+		read, ok := r.bind(c)
+		if !ok {
+			return nil, errResourceNotFound
+		}
+		return func(ctx context.Context) (any, error) { return read(ctx, "") }, nil
+	}
+
+	for _, t := range c.templates {
+		raw, ok := strings.CutPrefix(uri, t.prefix)
+		if !ok || raw == "" {
+			continue
+		}
+		arg, err := url.PathUnescape(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errResourceNotFound, err)
+		}
+		read, ok := t.bind(c)
+		if !ok {
+			return nil, errResourceNotFound
+		}
+		return func(ctx context.Context) (any, error) { return read(ctx, arg) }, nil
+	}
+
+	return nil, errResourceNotFound
+}
+
+// watch 返回订阅使用的读取函数：每次采样都重新解析 URI，数据来源工具被停用或注销后采样失败，
+// 不再推送更新；工具重新启用后恢复。
+func (c *resourceCatalog) watch(uri string) func(ctx context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		read, err := c.resolve(uri)
+		if err != nil {
+			return nil, err
+		}
+		return read(ctx)
+	}
+}
+
+// resourceMetric 提取资源数据中用于判断变化幅度的关键指标（百分比），
+// 没有合适指标的资源返回 false，仅按时间间隔推送更新。
+func resourceMetric(data any) (float64, bool) {
+	switch v := data.(type) {
+	case types.CPUInfo:
+		return v.Usage.Total, true
+	case types.MemoryInfo:
+		return v.UsedPercent, true
+	case types.DiskPartition:
+		return v.UsedPercent, true
+	case types.DiskInfo:
+		var highest float64
+		for _, partition := range v.Partitions {
+			highest = max(highest, partition.UsedPercent)
+		}
+		return highest, len(v.Partitions) > 0
+	case types.ProcessInfo:
+		return v.CPUPercent, true
+	default:
+		return 0, false
+	}
+}
+
+// readDiskPartition 查找指定挂载点的分区信息，未在分区表中找到时退化为按路径统计。
+func readDiskPartition(ctx context.Context, dt *tools.DiskTool, mountpoint string) (any, error) {
+	if diskInfo, err := dt.GetDiskData(ctx, true); err == nil {
+		for _, partition := range diskInfo.Partitions {
+			if partition.Mountpoint == mountpoint {
+				return partition, nil
+			}
+		}
+	}
+	return dt.GetDiskUsageByPath(ctx, mountpoint)
+}
+
+// readNetworkInterface 读取单个网络接口的流量统计。
+func readNetworkInterface(ctx context.Context, nt *tools.NetworkTool, name string) (any, error) {
+	netInfo, err := nt.GetNetworkData(ctx, false, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(netInfo.Interfaces) == 0 {
+		return nil, i18n.Errorf(i18n.FromContext(ctx), "resource.error.interface", errResourceNotFound, name)
+	}
+	return netInfo.Interfaces[0], nil
+}
@@ -1,21 +1,76 @@
 package router
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go-mcp/mcp/i18n"
 	"go-mcp/mcp/tools"
 	"go-mcp/mcp/types"
 	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
+// defaultMaxConcurrency 为默认的请求并发上限。
+const defaultMaxConcurrency = 16
+
 // Server implements a stdio-based MCP server.
 type Server struct {
 	input  io.Reader
 	output io.Writer
 
-	tools map[string]types.MonitorTool
+	// registry 保存可供调用的工具，变化时向已初始化的会话推送 notifications/tools/list_changed
+	registry *tools.Registry
+
+	// resources 与 prompts 的数据来自 registry 中的工具，随工具的启停出现或消失
+	resources *resourceCatalog
+	prompts   *promptCatalog
+
+	subscriptionOptions SubscriptionOptions
+
+	// workers 为请求处理的并发上限，容量即工作池大小
+	maxConcurrency int
+	workers        chan struct{}
+
+	// keepAliveInterval 为服务器主动 ping 的周期，为 0 时关闭；keepAliveTimeout 为等待应答的时长
+	keepAliveInterval time.Duration
+	keepAliveTimeout  time.Duration
+
+	// capabilities 为声明并提供的能力，instructions 在 initialize 结果中返回给客户端
+	capabilities Capabilities
+	instructions string
+
+	// logger 记录服务器运行日志，默认丢弃；stdio 传输下不得写入标准输出。
+	// 会话内的日志还会按客户端通过 logging/setLevel 设置的级别推送给客户端
+	logger *slog.Logger
+
+	// maxMessageSize 为单条消息的字节数上限，适用于所有传输
+	maxMessageSize int64
+
+	// defaultToolTimeout 为单次工具调用的执行时限，toolTimeouts 按工具名称覆盖；不大于 0 表示不限时
+	defaultToolTimeout time.Duration
+	toolTimeouts       map[string]time.Duration
+
+	// toolDefaults 按工具名称保存调用方未提供时使用的参数值
+	toolDefaults map[string]map[string]any
+
+	// allowedHosts 为本机地址之外允许出现在 Host 与 Origin 请求头中的主机名，均为小写
+	allowedHosts []string
+
+	// sessionIdleTimeout 为 Streamable HTTP 会话空闲多久后自动结束，不大于 0 表示不过期
+	sessionIdleTimeout time.Duration
+
+	// pageSize 为 tools/list 等列表请求每页的条目数
+	pageSize int
+
+	// locale 为默认输出语言，会话或单次调用可以覆盖
+	locale i18n.Locale
 
 	info types.ServerInfo
 
@@ -25,270 +80,777 @@ type Server struct {
 // Option customises server behavior during construction.
 type Option func(*Server)
 
+// WithSubscriptionOptions 设置资源订阅的采样周期与推送阈值。
+func WithSubscriptionOptions(opts SubscriptionOptions) Option {
+	return func(s *Server) {
+		s.subscriptionOptions = opts
+	}
+}
+
+// WithKeepAlive 启用服务器主动发起的 ping：每隔 interval 向客户端发送一次，
+// 客户端在 timeout 内未应答时关闭会话。interval 不大于 0 时不发送。
+func WithKeepAlive(interval, timeout time.Duration) Option {
+	return func(s *Server) {
+		s.keepAliveInterval = interval
+		s.keepAliveTimeout = timeout
+	}
+}
+
+// WithLocale 设置工具描述与输出的默认语言，客户端可在 initialize 或单次调用中覆盖。
+func WithLocale(locale i18n.Locale) Option {
+	return func(s *Server) {
+		s.locale = locale
+	}
+}
+
+// WithIO 设置 stdio 传输读写消息的流，默认为标准输入与标准输出。
+func WithIO(input io.Reader, output io.Writer) Option {
+	return func(s *Server) {
+		s.input = input
+		s.output = output
+	}
+}
+
+// WithServerInfo 设置 initialize 结果中返回的服务器名称与版本。
+func WithServerInfo(name, version string) Option {
+	return func(s *Server) {
+		s.info = types.ServerInfo{Name: name, Version: version}
+	}
+}
+
+// WithTools 以给定的工具集合替代内置工具，名称重复的工具只保留第一个。
+// 需要在运行时增删工具时改用 WithRegistry。
+func WithTools(monitorTools ...types.MonitorTool) Option {
+	return func(s *Server) {
+		s.registry = tools.NewRegistry()
+		for _, tool := range monitorTools {
+			s.registry.Register(tool)
+		}
+	}
+}
+
+// WithLogger 设置服务器日志的输出，默认不输出日志。日志级别由 logger 的 Handler 决定；
+// 推送给客户端的 notifications/message 不受其影响，只取决于客户端设置的级别。
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithInstructions 设置 initialize 结果中的 instructions，向客户端说明服务器的用途与用法。
+func WithInstructions(instructions string) Option {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// WithCapabilities 设置服务器声明并提供的能力，默认为 DefaultCapabilities()。
+func WithCapabilities(capabilities Capabilities) Option {
+	return func(s *Server) {
+		s.capabilities = capabilities
+	}
+}
+
+// WithRegistry 使用调用方提供的工具注册表，替代内置工具。嵌入本服务器的应用可以借此
+// 注册自己的 MonitorTool，并在运行时增删工具。
+func WithRegistry(registry *tools.Registry) Option {
+	return func(s *Server) {
+		s.registry = registry
+	}
+}
+
+// WithMaxMessageSize 设置单条 JSON-RPC 消息的字节数上限，超过上限的消息会收到错误响应，
+// 不影响连接上的后续消息。不大于 0 时使用默认值 4 MiB。
+func WithMaxMessageSize(n int64) Option {
+	return func(s *Server) {
+		s.maxMessageSize = n
+	}
+}
+
+// WithPageSize 设置 tools/list 每页返回的条目数，不大于 0 时使用默认值。
+func WithPageSize(n int) Option {
+	return func(s *Server) {
+		s.pageSize = n
+	}
+}
+
+// WithToolTimeout 设置单次工具调用的执行时限，默认为 30 秒，不大于 0 时不限时。
+// 超时的调用以 isError 结果返回给客户端，不必等待阻塞的采集项。
+func WithToolTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.defaultToolTimeout = timeout
+	}
+}
+
+// WithToolTimeouts 按工具名称设置执行时限，覆盖 WithToolTimeout 的默认值。
+func WithToolTimeouts(timeouts map[string]time.Duration) Option {
+	return func(s *Server) {
+		for name, timeout := range timeouts {
+			s.toolTimeouts[name] = timeout
+		}
+	}
+}
+
+// WithToolDefaults 按工具名称设置默认参数，调用方未提供的参数取这里的值，
+// 其优先级高于工具输入模式中声明的默认值。默认参数同样需要符合工具的输入模式。
+func WithToolDefaults(defaults map[string]map[string]any) Option {
+	return func(s *Server) {
+		for name, values := range defaults {
+			s.toolDefaults[name] = values
+		}
+	}
+}
+
+// WithAllowedHosts 允许 HTTP、SSE 与 WebSocket 传输接受本机地址以外的主机名，
+// 如 "mcp.example.com" 或 "192.168.1.10"（不含端口，IPv6 地址可带方括号）。
+// 请求的 Host 请求头与 Origin 的主机名不在允许范围内时返回 403，以防 DNS 重绑定攻击。
+func WithAllowedHosts(hosts ...string) Option {
+	return func(s *Server) {
+		for _, host := range hosts {
+			host = strings.ToLower(strings.Trim(host, "[]"))
+			if host != "" {
+				s.allowedHosts = append(s.allowedHosts, host)
+			}
+		}
+	}
+}
+
+// WithSessionIdleTimeout 设置 Streamable HTTP 会话在没有任何请求使用时保留的时长，
+// 超时后会话自动结束；不大于 0 表示只在客户端发送 DELETE 或服务器关闭时结束。
+func WithSessionIdleTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.sessionIdleTimeout = timeout
+	}
+}
+
+// WithMaxConcurrency 设置同时处理的请求数上限。
+func WithMaxConcurrency(n int) Option {
+	return func(s *Server) {
+		s.maxConcurrency = n
+	}
+}
+
 // NewServer 构建一个基于 stdio 的 MCP 服务器，并在初始化阶段绑定所有已注册工具。
-func NewServer() *Server {
-	return &Server{
-		input:  os.Stdin,
-		output: os.Stdout,
-		tools:  make(map[string]types.MonitorTool),
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		input:               os.Stdin,
+		output:              os.Stdout,
+		subscriptionOptions: DefaultSubscriptionOptions(),
+		maxConcurrency:      defaultMaxConcurrency,
+		capabilities:        DefaultCapabilities(),
+		defaultToolTimeout:  defaultToolTimeout,
+		toolTimeouts:        make(map[string]time.Duration),
+		toolDefaults:        make(map[string]map[string]any),
+		sessionIdleTimeout:  defaultSessionIdleTimeout,
+		logger:              slog.New(slog.DiscardHandler),
+		locale:              i18n.Default,
 		info: types.ServerInfo{
 			Name:    "go-mcp-server",
 			Version: "dev",
 		},
 	}
-}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.maxConcurrency <= 0 {
+		s.maxConcurrency = defaultMaxConcurrency
+	}
+	s.workers = make(chan struct{}, s.maxConcurrency)
+	if s.logger == nil {
+		s.logger = slog.New(slog.DiscardHandler)
+	}
+	if s.maxMessageSize <= 0 {
+		s.maxMessageSize = defaultMaxMessageSize
+	}
+	if s.pageSize <= 0 {
+		s.pageSize = defaultPageSize
+	}
+	if s.keepAliveTimeout <= 0 {
+		s.keepAliveTimeout = defaultKeepAliveTimeout
+	}
 
-// Run processes JSON-RPC messages until the input stream is closed or the context is cancelled.
-func (s *Server) Run() error {
-	if s.initialized {
-		return fmt.Errorf("路由器已经在运行")
+	// 未提供注册表时注册内置工具
+	if s.registry == nil {
+		s.registry = tools.NewRegistry()
+		s.InitializeTools()
 	}
+	s.resources = newResourceCatalog(s.registry, s.toolTimeout)
+	s.prompts = newPromptCatalog(s.registry, s.toolTimeout)
 
-	// 启动 MCP 路由器，但不输出日志避免干扰 JSON-RPC
-	s.initialized = true
+	return s
+}
 
-	// 初始化工具
-	if err := s.InitializeTools(); err != nil {
-		return fmt.Errorf("初始化工具失败: %v", err)
-	}
+// Registry 返回服务器使用的工具注册表，可在运行时注册、注销、启用或停用工具。
+func (s *Server) Registry() *tools.Registry {
+	return s.registry
+}
 
-	// 启动消息处理循环
-	return s.dispatch()
+// Run processes JSON-RPC messages from the input stream until it is closed.
+func (s *Server) Run() error {
+	return s.Serve(context.Background(), StdioTransport())
 }
 
 // InitializeTools 初始化所有监控工具
 func (s *Server) InitializeTools() error {
-	// 初始化监控工具，但不输出日志避免干扰 JSON-RPC
-
-	// 创建工具实例
-	cpuTool := tools.NewCPUTool()
-	diskTool := tools.NewDiskTool()
-	memoryTool := tools.NewMemoryTool()
-	networkTool := tools.NewNetworkTool()
-	processTool := tools.NewProcessTool()
-	systemTool := tools.NewSystemTool()
-
-	// 注册工具
-	s.tools[cpuTool.GetName()] = cpuTool
-	s.tools[diskTool.GetName()] = diskTool
-	s.tools[memoryTool.GetName()] = memoryTool
-	s.tools[networkTool.GetName()] = networkTool
-	s.tools[processTool.GetName()] = processTool
-	s.tools[systemTool.GetName()] = systemTool
-
-	// 工具初始化完成，但不输出日志避免干扰 JSON-RPC
-
-	return nil
+	return tools.RegisterBuiltins(s.registry)
 }
 
-func (s *Server) dispatch() error {
-	scanner := bufio.NewScanner(s.input)
-	for s.initialized && scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+// dispatch 逐条读取输入流中的消息并处理，直至输入结束或 gate 被停止
+func (s *Server) dispatch(sess *session, gate *dispatchGate) error {
+	reader := newMessageReader(s.input, s.maxMessageSize)
+	for {
+		data, err := reader.next()
+		if errors.Is(err, errMessageTooLarge) {
+			// 超长消息已被丢弃，回复错误后继续处理后续消息
+			if !gate.do(func() { sess.send(s.messageTooLargeResponse()) }) {
+				return nil
+			}
 			continue
 		}
-
-		// 解析 JSON-RPC 请求
-		var req types.Request
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
-			// 解析失败，但不输出日志到避免干扰 JSON-RPC
-			// 发送解析错误响应（只有在有ID的情况下）
-			var rawMessage map[string]json.RawMessage
-			json.Unmarshal([]byte(line), &rawMessage)
-			if id, hasID := rawMessage["id"]; hasID {
-				errorResp := &types.Response{
-					JSONRPC: "2.0",
-					ID:      id,
-					Error: &types.Error{
-						Code:    -32700,
-						Message: "Parse error: " + err.Error(),
-					},
-				}
-				s.writeResponse(errorResp)
-			}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// 读取错误，但不输出到 stdout
+			return fmt.Errorf("读取输入时出错: %v", err)
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
 			continue
 		}
 
-		// 检查是否是通知（没有 ID 字段）
-		isNotification := req.ID == nil
+		// 请求并发处理，响应可能乱序返回
+		if !gate.do(func() { s.serveMessage(sess, data) }) {
+			return nil
+		}
+	}
+}
+
+// dispatchGate 使 serveStdio 能在返回前停止读取循环。阻塞在输入流上的读取无法中断，
+// 但读取循环在持有锁时检查是否已停止，因此 stop 返回后不会再有消息被处理或登记为在途请求。
+type dispatchGate struct {
+	mu      sync.Mutex
+	stopped bool
+}
+
+// do 在未停止时执行 fn 并返回 true
+func (g *dispatchGate) do(fn func()) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		return false
+	}
+	fn()
+	return true
+}
+
+// stop 停止读取循环，等待正在执行的 do 返回
+func (g *dispatchGate) stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.stopped = true
+}
+
+// serveMessage 解析一条原始 JSON-RPC 消息或批量请求：通知立即处理，请求交由工作池并发执行，
+// 处理完成后经会话发出响应。
+func (s *Server) serveMessage(sess *session, data []byte) {
+	// 批量请求整体在后台处理，全部完成后以一个数组回复
+	if isBatch(data) {
+		sess.wg.Add(1)
+		go func() {
+			defer sess.wg.Done()
+			if reply := s.processBatch(sess, data, nil); reply != nil {
+				sess.send(reply)
+			}
+		}()
+		return
+	}
+
+	// 客户端对服务器所发请求（如 ping）的响应
+	if response, ok := decodeResponse(data); ok {
+		sess.deliver(response)
+		return
+	}
+
+	// 解析并校验 JSON-RPC 请求，无法解析的消息也要回复（ID 为 null）
+	req, errResp := decodeMessage(data)
+	if errResp != nil {
+		sess.send(errResp)
+		return
+	}
+	if req == nil {
+		return
+	}
+
+	// 通知（没有 ID 字段）开销很小且无需响应，直接处理；
+	// 这也保证了 notifications/cancelled 不会排在被取消的请求之后
+	if req.ID == nil {
+		s.handleRequest(sess.ctx, sess, req)
+		return
+	}
 
-		// 处理请求
-		response := s.handleRequest(&req)
+	// 在读取循环中同步登记，保证重复 ID 的判定与消息到达顺序一致
+	ctx, ok := sess.track(req.ID)
+	if !ok {
+		sess.send(s.duplicateIDResponse(req))
+		return
+	}
+	ctx, errResp = s.admitRequest(ctx, sess, req)
+	if errResp != nil {
+		sess.untrack(req.ID)
+		sess.send(errResp)
+		return
+	}
 
-		// 发送响应（只有非通知的请求才发送响应）
-		if response != nil && !isNotification {
-			s.writeResponse(response)
+	// initialize 开销很小，同步处理以保证紧随其后的请求能看到握手结果
+	if req.Method == types.MethodInitialize {
+		defer sess.untrack(req.ID)
+		sess.send(s.handleRequest(ctx, sess, req))
+		return
+	}
+
+	sess.wg.Add(1)
+	go func() {
+		defer sess.wg.Done()
+		defer sess.untrack(req.ID)
+
+		response := s.execute(ctx, sess, req)
+		// 已被客户端取消的请求不再发送响应
+		if response != nil && !cancelledByClient(ctx) {
+			sess.send(response)
 		}
+	}()
+}
+
+// processRequest 登记在途请求并同步处理，供每个请求自带 goroutine 的传输（如 HTTP）使用；
+// sink 非空时与该请求相关的通知（如进度）将经由它发出，而不是会话的公共通道
+func (s *Server) processRequest(sess *session, req *types.Request, sink messageSink) *types.Response {
+	ctx, ok := sess.track(req.ID)
+	if !ok {
+		return s.duplicateIDResponse(req)
 	}
+	defer sess.untrack(req.ID)
 
-	if err := scanner.Err(); err != nil {
-		// 扫描错误，但不输出到 stdout
-		return fmt.Errorf("扫描输入时出错: %v", err)
+	ctx, errResp := s.admitRequest(ctx, sess, req)
+	if errResp != nil {
+		return errResp
+	}
+	if sink != nil {
+		ctx = withRequestSink(ctx, sink)
 	}
 
-	return nil
+	sess.wg.Add(1)
+	defer sess.wg.Done()
+
+	return s.execute(ctx, sess, req)
 }
 
-func (s *Server) handleRequest(req *types.Request) *types.Response {
+// execute 在工作池中处理请求，工作池已满时等待空闲；等待期间请求被取消则直接放弃
+func (s *Server) execute(ctx context.Context, sess *session, req *types.Request) *types.Response {
+	select {
+	case s.workers <- struct{}{}:
+	case <-ctx.Done():
+		return s.errorResponse(req, types.CodeInternalError, "Request cancelled: "+context.Cause(ctx).Error())
+	}
+	defer func() { <-s.workers }()
+
+	return s.handleRequest(ctx, sess, req)
+}
+
+// duplicateIDResponse 拒绝与在途请求 ID 重复的请求
+func (s *Server) duplicateIDResponse(req *types.Request) *types.Response {
+	return s.errorResponseFor(req, types.NewError(types.CodeInvalidRequest, "Invalid Request: duplicate request id "+string(req.ID)).
+		WithData(types.ErrorData{Field: "id"}))
+}
+
+// handleRequest 处理一条请求或通知；处理过程中的 panic 只影响当前请求，以内部错误响应
+func (s *Server) handleRequest(ctx context.Context, sess *session, req *types.Request) (response *types.Response) {
+	defer func() {
+		if value := recover(); value != nil {
+			response = s.recoverResponse(sess, req, value)
+		}
+	}()
+
+	// 握手完成前只接受 initialize，其余请求一律拒绝，通知直接忽略
+	if !sess.initialized() && !allowedBeforeInitialize(req.Method) {
+		if req.ID == nil {
+			return nil
+		}
+		return s.notInitializedResponse(req)
+	}
+
+	// 关闭的能力对客户端不可见，其方法按未知方法处理
+	if !s.capabilities.allows(req.Method) {
+		return s.methodNotFoundResponse(req)
+	}
+
+	ctx = i18n.WithLocale(ctx, sess.outputLocale(s.locale))
+
 	switch req.Method {
+	case types.MethodPing:
+		return s.handlePing(req)
 	case types.MethodInitialize:
-		return s.handleInitialize(req)
-	//case types.MethodInitialized, types.MethodNotificationInitialized:
-	//	return s.handleInitialized(req)
+		return s.handleInitialize(sess, req)
+	case types.MethodInitialized, types.MethodNotificationInitialized:
+		return s.handleInitialized(sess)
 	case types.MethodListTools:
-		return s.handleListTools(req)
+		return s.handleListTools(ctx, sess, req)
 	case types.MethodCallTool:
-		return s.handleCallTool(req)
-	//case types.MethodListPrompts:
-	//	return s.handleListPrompts(req)
-	//case types.MethodListResources:
-	//	return s.handleListResources(req)
-	//case types.MethodReadResource:
-	//	return s.handleReadResource(req)
+		return s.handleCallTool(ctx, sess, req)
+	case types.MethodListPrompts:
+		return s.handleListPrompts(ctx, req)
+	case types.MethodGetPrompt:
+		return s.handleGetPrompt(ctx, req)
+	case types.MethodListResources:
+		return s.handleListResources(ctx, req)
+	case types.MethodListResourceTemplates:
+		return s.handleListResourceTemplates(ctx, req)
+	case types.MethodReadResource:
+		return s.handleReadResource(ctx, req)
+	case types.MethodSubscribeResource:
+		return s.handleSubscribeResource(sess, req)
+	case types.MethodUnsubscribeResource:
+		return s.handleUnsubscribeResource(sess, req)
+	case types.MethodNotificationCancelled:
+		return s.handleCancelled(sess, req)
+	case types.MethodSetLevel:
+		return s.handleSetLevel(sess, req)
 	default:
-		return s.errorResponse(req, -32601, "Method not found: "+req.Method)
+		return s.methodNotFoundResponse(req)
 	}
 }
 
-// sendResponse 发送响应
-func (s *Server) writeResponse(response *types.Response) {
-	respBytes, err := json.Marshal(response)
-	if err != nil {
-		// 序列化失败，但不输出日志避免干扰 JSON-RPC
-		return
-	}
+// methodNotFoundResponse 创建未知方法的错误响应
+func (s *Server) methodNotFoundResponse(req *types.Request) *types.Response {
+	return s.errorResponseFor(req, types.NewError(types.CodeMethodNotFound, "Method not found: "+req.Method).
+		WithData(types.ErrorData{Method: req.Method}))
+}
 
-	if _, err := fmt.Fprintln(s.output, string(respBytes)); err != nil {
-		// 发送失败，但不输出日志避免干扰 JSON-RPC
+// handleInitialize 处理初始化请求：协商协议版本并记录客户端信息
+func (s *Server) handleInitialize(sess *session, req *types.Request) *types.Response {
+	// 客户端可能声明服务器不认识的能力，因此不使用严格解码
+	var params types.InitializeParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.errorResponseFor(req, types.NewInvalidParamsError(fmt.Errorf("Invalid params: %v", err)).
+			WithData(types.ErrorData{Method: req.Method, Detail: err.Error()}))
+	}
+	if params.ProtocolVersion == "" {
+		return s.missingParamResponse(req, "protocolVersion")
 	}
-}
 
-// handleInitialize 处理初始化请求
-func (s *Server) handleInitialize(req *types.Request) *types.Response {
-	// 初始化服务器，但不输出日志避免干扰 JSON-RPC
+	version, ok := sess.beginInitialize(params)
+	if !ok {
+		return s.errorResponseFor(req, types.NewError(types.CodeInvalidRequest, "Invalid Request: session already initialized").
+			WithData(types.ErrorData{Method: req.Method}))
+	}
+	sess.logger.Info("客户端已连接", "client", params.ClientInfo.Name, "clientVersion", params.ClientInfo.Version,
+		"protocolVersion", version)
 
-	result := types.InitializeResult{
-		ProtocolVersion: "2024-11-05",
-		Capabilities: types.ServerCapabilities{
-			Tools: &types.ToolsCapability{
-				ListChanged: true,
-			},
-			Resources: &types.ResourcesCapability{
-				Subscribe:   false,
-				ListChanged: false,
-			},
-			Prompts: &types.PromptsCapability{
-				ListChanged: false,
-			},
-		},
+	return s.resultResponse(req, types.InitializeResult{
+		ProtocolVersion: version,
+		Capabilities:    s.capabilities.declared(),
 		ServerInfo: types.ServerInfo{
 			Name:    s.info.Name,
 			Version: s.info.Version,
 		},
-	}
+		Instructions: s.instructions,
+	})
+}
 
-	// 先编码成 JSON
-	resultJson, err := json.Marshal(result)
-	if err != nil {
-		panic(err)
-	}
+// handleInitialized 处理客户端的 initialized 通知，握手至此完成
+func (s *Server) handleInitialized(sess *session) *types.Response {
+	sess.markInitialized()
+	return nil
+}
 
-	return &types.Response{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result:  resultJson,
-	}
+// handlePing 处理 ping 请求，按 MCP 规范以空对象应答
+func (s *Server) handlePing(req *types.Request) *types.Response {
+	return s.resultResponse(req, types.PingResult{})
 }
 
 // handleListTools 处理工具列表请求
-func (s *Server) handleListTools(req *types.Request) *types.Response {
-	// 列出工具，但不输出日志避免干扰 JSON-RPC
+func (s *Server) handleListTools(ctx context.Context, sess *session, req *types.Request) *types.Response {
+	var params types.ListToolsParams
+	if errResp := s.decodeParams(req, &params); errResp != nil {
+		return errResp
+	}
 
-	var toolDefinitions []types.ToolDefinition
-	for _, tool := range s.tools {
+	// 按名称排序后分页，保证多次调用之间顺序稳定
+	page, nextCursor, err := paginate(s.registry.Names(), params.Cursor, s.pageSize)
+	if err != nil {
+		return s.errorResponseFor(req, types.NewErrorf(types.CodeInvalidParams, "Invalid params: %v", err).
+			WithData(types.ErrorData{Field: "cursor", Method: req.Method}))
+	}
+
+	features := requestFeatures(ctx, sess)
+	locale := i18n.FromContext(ctx)
+
+	toolDefinitions := make([]types.ToolDefinition, 0, len(page))
+	for _, name := range page {
+		// 列表生成期间工具可能已被注销或停用
+		tool, exists := s.registry.Lookup(name)
+		if !exists {
+			continue
+		}
 		mcpTool := types.ToolDefinition{
 			Name:        tool.GetName(),
-			Description: tool.GetDescription(),
-			InputSchema: tool.GetInputSchema(),
+			Description: tool.GetDescription(locale),
+			InputSchema: tool.GetInputSchema(locale),
+		}
+		// 旧版本协议没有 outputSchema、annotations 与 title 字段
+		if features.structuredContent {
+			outputSchema := tool.GetOutputSchema()
+			mcpTool.OutputSchema = &outputSchema
+		}
+		if features.toolAnnotations {
+			annotations := tool.GetAnnotations(locale)
+			mcpTool.Annotations = &annotations
+			if features.toolTitle {
+				mcpTool.Title = annotations.Title
+			}
 		}
 		toolDefinitions = append(toolDefinitions, mcpTool)
 	}
 
-	result := map[string]interface{}{
-		"tools": toolDefinitions,
-	}
+	return s.resultResponse(req, types.ListToolsResult{
+		Tools:      toolDefinitions,
+		NextCursor: nextCursor,
+	})
+}
 
-	// 先编码成 JSON
-	resultJson, err := json.Marshal(result)
-	if err != nil {
-		panic(err)
+func (s *Server) handleCallTool(ctx context.Context, sess *session, req *types.Request) *types.Response {
+	var params types.CallToolParams
+	if errResp := s.decodeParams(req, &params); errResp != nil {
+		return errResp
+	}
+	if params.Name == "" {
+		return s.missingParamResponse(req, "name")
 	}
 
-	return &types.Response{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result:  resultJson,
+	// 查找工具
+	tool, exists := s.registry.Lookup(params.Name)
+	if !exists {
+		return s.errorResponseFor(req, types.NewToolError(fmt.Errorf("Unknown tool: %s", params.Name)).
+			WithData(types.ErrorData{Field: "name", Tool: params.Name}))
 	}
-}
 
-func (s *Server) handleCallTool(req *types.Request) *types.Response {
-	var params types.CallToolParams
-	if req.Params != nil {
-		paramBytes, err := json.Marshal(req.Params)
-		if err != nil {
-			return s.errorResponse(req, -32602, "Invalid params: "+err.Error())
+	// 调用方未提供的参数取配置的默认值
+	params.Arguments = s.withToolDefaults(params.Name, params.Arguments)
+
+	// 单次调用可以通过 _meta.locale 或 locale 参数切换语言，参数优先
+	if params.Meta != nil {
+		if locale, ok := i18n.Parse(params.Meta.Locale); ok {
+			ctx = i18n.WithLocale(ctx, locale)
 		}
-		if err := json.Unmarshal(paramBytes, &params); err != nil {
-			return s.errorResponse(req, -32602, "Invalid params: "+err.Error())
+	}
+	if tag, ok := params.Arguments[i18n.Argument].(string); ok && tag != "" {
+		locale, ok := i18n.Parse(tag)
+		if !ok {
+			return s.toolErrorResult(req, i18n.Errorf(i18n.FromContext(ctx), "error.locale", tag))
 		}
+		ctx = i18n.WithLocale(ctx, locale)
 	}
 
-	// 调用工具，但不输出日志避免干扰 JSON-RPC
+	// 参数不符合输入模式属于协议错误，不交给工具处理
+	if err := tool.GetInputSchema(i18n.FromContext(ctx)).Validate(params.Arguments); err != nil {
+		return s.invalidArgumentsResponse(req, params.Name, err)
+	}
 
-	// 查找工具
-	tool, exists := s.tools[params.Name]
-	if !exists {
-		return s.errorResponse(req, -32602, "Unknown tool: "+params.Name)
+	// 客户端提供 progressToken 时向工具注入进度汇报器
+	if params.Meta != nil && len(params.Meta.ProgressToken) > 0 {
+		ctx = types.WithProgressReporter(ctx, newProgressNotifier(ctx, sess, params.Meta.ProgressToken))
 	}
 
+	// 工具通过 types.LoggerFrom 记录的诊断信息同样会推送给客户端
+	logger := sess.logger.With("tool", params.Name)
+	ctx = types.WithLogger(ctx, logger)
+
 	// 执行工具
-	result, err := tool.Execute(params.Arguments)
+	start := time.Now()
+	result, err := s.callTool(ctx, tool, params.Arguments)
 	if err != nil {
-		// 工具执行失败，但不输出日志避免干扰 JSON-RPC
+		logger.WarnContext(ctx, "工具执行失败", "error", err, "duration", time.Since(start))
+		return s.toolErrorResult(req, err)
+	}
+	logger.DebugContext(ctx, "工具执行完成", "duration", time.Since(start))
 
-		result := types.CallToolResult{
-			Content: []types.ContentItem{
-				{Type: "text", Text: "❌ " + err.Error()},
-			},
-			IsError: true,
-		}
+	// 文本内容保留给不支持结构化结果的客户端阅读
+	callToolResult := types.CallToolResult{
+		Content: []types.ContentItem{
+			{Type: "text", Text: result.Text},
+		},
+	}
+	if requestFeatures(ctx, sess).structuredContent {
+		callToolResult.StructuredContent = result.Data
+	}
 
-		// 先编码成 JSON
-		resultJson, err := json.Marshal(result)
-		if err != nil {
-			panic(err)
-		}
+	return s.resultResponse(req, callToolResult)
+}
+
+// handleListResources 处理资源列表请求
+func (s *Server) handleListResources(ctx context.Context, req *types.Request) *types.Response {
+	return s.resultResponse(req, types.ListResourcesResult{
+		Resources: s.resources.resources(i18n.FromContext(ctx)),
+	})
+}
+
+// handleListResourceTemplates 处理资源模板列表请求
+func (s *Server) handleListResourceTemplates(ctx context.Context, req *types.Request) *types.Response {
+	return s.resultResponse(req, types.ListResourceTemplatesResult{
+		ResourceTemplates: s.resources.resourceTemplates(i18n.FromContext(ctx)),
+	})
+}
 
-		return &types.Response{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Result:  resultJson,
+// handleReadResource 处理资源读取请求
+func (s *Server) handleReadResource(ctx context.Context, req *types.Request) *types.Response {
+	var params types.ReadResourceParams
+	if errResp := s.decodeParams(req, &params); errResp != nil {
+		return errResp
+	}
+	if params.URI == "" {
+		return s.missingParamResponse(req, "uri")
+	}
+
+	contents, err := s.resources.read(ctx, params.URI)
+	if errors.Is(err, errResourceNotFound) {
+		return s.unknownResourceResponse(req, params.URI, err)
+	}
+	if err != nil {
+		return s.errorResponseFor(req, types.NewErrorf(types.CodeApplicationError, "Read resource failed: %v", err).
+			WithData(types.ErrorData{URI: params.URI}))
+	}
+
+	return s.resultResponse(req, types.ReadResourceResult{
+		Contents: []types.ResourceContents{contents},
+	})
+}
+
+// handleSubscribeResource 处理资源订阅请求
+func (s *Server) handleSubscribeResource(sess *session, req *types.Request) *types.Response {
+	var params types.SubscribeParams
+	if errResp := s.decodeParams(req, &params); errResp != nil {
+		return errResp
+	}
+	if params.URI == "" {
+		return s.missingParamResponse(req, "uri")
+	}
+
+	if _, err := s.resources.resolve(params.URI); err != nil {
+		return s.unknownResourceResponse(req, params.URI, err)
+	}
+	sess.subscriptions.subscribe(params.URI, s.resources.watch(params.URI))
+
+	return s.resultResponse(req, struct{}{})
+}
+
+// handleUnsubscribeResource 处理取消资源订阅请求
+func (s *Server) handleUnsubscribeResource(sess *session, req *types.Request) *types.Response {
+	var params types.SubscribeParams
+	if errResp := s.decodeParams(req, &params); errResp != nil {
+		return errResp
+	}
+	if params.URI == "" {
+		return s.missingParamResponse(req, "uri")
+	}
+
+	sess.subscriptions.unsubscribe(params.URI)
+
+	return s.resultResponse(req, struct{}{})
+}
+
+// handleListPrompts 处理提示模板列表请求
+func (s *Server) handleListPrompts(ctx context.Context, req *types.Request) *types.Response {
+	return s.resultResponse(req, types.ListPromptsResult{
+		Prompts: s.prompts.list(i18n.FromContext(ctx)),
+	})
+}
+
+// handleGetPrompt 处理提示模板获取请求
+func (s *Server) handleGetPrompt(ctx context.Context, req *types.Request) *types.Response {
+	var params types.GetPromptParams
+	if errResp := s.decodeParams(req, &params); errResp != nil {
+		return errResp
+	}
+	if params.Name == "" {
+		return s.missingParamResponse(req, "name")
+	}
+
+	result, err := s.prompts.get(ctx, params.Name, params.Arguments, s.runTool)
+	if errors.Is(err, errPromptNotFound) {
+		return s.errorResponseFor(req, types.NewErrorf(types.CodeInvalidParams, "Unknown prompt: %s", params.Name).
+			WithData(types.ErrorData{Field: "name", Prompt: params.Name}))
+	}
+	if err != nil {
+		data := types.ErrorData{Field: "arguments", Prompt: params.Name}
+		var argErr *types.ArgumentError
+		if errors.As(err, &argErr) {
+			data.Field = "arguments." + argErr.Field
 		}
+		return s.errorResponseFor(req, types.NewInvalidParamsError(err).WithData(data))
 	}
 
-	// 工具执行成功，但不输出日志避免干扰 JSON-RPC
+	return s.resultResponse(req, result)
+}
 
-	callToolResult := types.CallToolResult{
+// runTool 执行已注册的工具，供提示模板填充实时数据
+func (s *Server) runTool(ctx context.Context, name string, args map[string]interface{}) (string, error) {
+	tool, exists := s.registry.Lookup(name)
+	if !exists {
+		return "", fmt.Errorf("unknown tool: %s", name)
+	}
+	output, err := s.callTool(ctx, tool, s.withToolDefaults(name, args))
+	if err != nil {
+		return "", err
+	}
+	return output.Text, nil
+}
+
+// withToolDefaults 以配置的默认参数补全调用方未提供的参数，不修改传入的 args
+func (s *Server) withToolDefaults(name string, args map[string]interface{}) map[string]interface{} {
+	defaults := s.toolDefaults[name]
+	if len(defaults) == 0 {
+		return args
+	}
+
+	merged := make(map[string]interface{}, len(defaults)+len(args))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range args {
+		merged[key] = value
+	}
+	return merged
+}
+
+// handleCancelled 处理客户端的取消通知，取消对应的在途请求
+func (s *Server) handleCancelled(sess *session, req *types.Request) *types.Response {
+	var params types.CancelledParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params.RequestID) == 0 {
+		// 通知无法回复错误，忽略格式不正确的取消请求
+		return nil
+	}
+
+	// 请求可能已经完成，此时按规范忽略该通知
+	sess.cancelRequest(params.RequestID)
+	return nil
+}
+
+// toolErrorResult 将工具执行失败作为 isError 结果返回，使模型能够看到错误并自行调整
+func (s *Server) toolErrorResult(req *types.Request, err error) *types.Response {
+	return s.resultResponse(req, types.CallToolResult{
 		Content: []types.ContentItem{
-			{Type: "text", Text: result},
+			{Type: "text", Text: "❌ " + err.Error()},
 		},
-	}
+		IsError: true,
+	})
+}
 
-	// 先编码成 JSON
-	resultJson, err := json.Marshal(callToolResult)
+// resultResponse 将结果编码为成功响应
+func (s *Server) resultResponse(req *types.Request, result any) *types.Response {
+	resultJson, err := json.Marshal(result)
 	if err != nil {
-		panic(err)
+		return s.errorResponse(req, types.CodeInternalError, "Internal error: "+err.Error())
 	}
 
 	return &types.Response{
@@ -298,10 +860,52 @@ func (s *Server) handleCallTool(req *types.Request) *types.Response {
 	}
 }
 
+// decodeParams 解码请求参数（忽略未知字段，如扩展的 _meta 键），失败时返回 Invalid Params 错误响应
+func (s *Server) decodeParams(req *types.Request, target any) *types.Response {
+	if err := types.DecodeParams(req.Params, target); err != nil {
+		return s.errorResponseFor(req, types.NewInvalidParamsError(fmt.Errorf("Invalid params: %v", err)).
+			WithData(types.ErrorData{Method: req.Method, Detail: err.Error()}))
+	}
+	return nil
+}
+
+// missingParamResponse 创建缺少必填参数的错误响应
+func (s *Server) missingParamResponse(req *types.Request, field string) *types.Response {
+	return s.errorResponseFor(req, types.NewErrorf(types.CodeInvalidParams, "Invalid params: missing %s", field).
+		WithData(types.ErrorData{Field: field, Method: req.Method}))
+}
+
+// invalidArgumentsResponse 创建工具参数不符合输入模式的错误响应，Field 指向出错的参数
+func (s *Server) invalidArgumentsResponse(req *types.Request, tool string, err error) *types.Response {
+	data := types.ErrorData{Tool: tool, Detail: err.Error()}
+	var argErr *types.ArgumentError
+	if errors.As(err, &argErr) {
+		data.Field = "arguments." + argErr.Field
+	}
+	return s.errorResponseFor(req, types.NewErrorf(types.CodeInvalidParams, "Invalid params: %v", err).WithData(data))
+}
+
+// unknownResourceResponse 创建资源不存在的错误响应
+func (s *Server) unknownResourceResponse(req *types.Request, uri string, err error) *types.Response {
+	data := types.ErrorData{Field: "uri", URI: uri}
+	if err != errResourceNotFound {
+		// 带有具体原因的包装错误（如无效的 PID）
+		data.Detail = err.Error()
+	}
+	return s.errorResponseFor(req, types.NewErrorf(types.CodeInvalidParams, "Unknown resource: %s", uri).WithData(data))
+}
+
+// errorResponseFor 将错误对象包装为响应
+func (s *Server) errorResponseFor(req *types.Request, e *types.Error) *types.Response {
+	return &types.Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error:   e,
+	}
+}
+
 // errorResponse 创建错误响应
 func (s *Server) errorResponse(req *types.Request, code int, message string) *types.Response {
-	// 创建错误响应，但不输出日志避免干扰 JSON-RPC
-
 	return &types.Response{
 		JSONRPC: "2.0",
 		ID:      req.ID,
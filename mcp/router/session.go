@@ -0,0 +1,288 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/types"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// errRequestCancelled 作为客户端通过 notifications/cancelled 取消请求时的 context 取消原因。
+var errRequestCancelled = errors.New("request cancelled by client")
+
+// messageSink 接收服务器发往客户端的消息（响应或通知），由各传输层实现。
+type messageSink interface {
+	write(message any) error
+}
+
+// session 表示一个客户端连接的会话状态。stdio 传输只有一个会话，
+// HTTP 传输则为每个 Mcp-Session-Id 维护一个会话。
+type session struct {
+	id            string
+	sink          messageSink
+	subscriptions *subscriptionManager
+
+	// 生命周期状态及 initialize 握手的协商结果
+	stateMu            sync.RWMutex
+	state              sessionState
+	protocolVersion    string
+	clientInfo         types.ClientInfo
+	clientCapabilities types.ClientCapabilities
+	// locale 为客户端在 initialize 的 _meta.locale 中声明的语言，为空时使用服务器默认语言
+	locale i18n.Locale
+	// logLevel 为客户端通过 logging/setLevel 设置的最低日志级别，logToClient 为 false 时不推送日志
+	logLevel    slog.Level
+	logToClient bool
+
+	// logger 写入服务器日志并按客户端设置的级别推送 notifications/message；
+	// local 只写入服务器日志，用于消息发送失败等不能再推送给客户端的情况
+	logger *slog.Logger
+	local  *slog.Logger
+
+	// ctx 为会话内所有请求的父 context，连接断开时取消
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// inflight 记录正在处理的请求及其取消函数，用于拒绝重复 ID、响应取消通知并在关闭时等待请求完成
+	mu       sync.Mutex
+	inflight map[string]context.CancelCauseFunc
+	wg       sync.WaitGroup
+
+	// pending 记录服务器发往客户端、尚未收到响应的请求，与 inflight 共用 mu
+	pending map[string]chan *types.Response
+	nextID  atomic.Int64
+
+	// unwatchTools 取消对工具注册表变化的监听
+	unwatchTools func()
+}
+
+// newSession 创建会话，服务器推送的消息经由 sink 发往客户端。
+func (s *Server) newSession(id string, sink messageSink) *session {
+	ctx, cancel := context.WithCancel(context.Background())
+	sess := &session{
+		id:       id,
+		sink:     sink,
+		ctx:      ctx,
+		cancel:   cancel,
+		inflight: make(map[string]context.CancelCauseFunc),
+		pending:  make(map[string]chan *types.Response),
+	}
+	sess.local = s.logger
+	if id != "" {
+		sess.local = s.logger.With("session", id)
+	}
+	sess.logger = newClientLogger(sess.local, sess, s.info.Name)
+	sess.subscriptions = newSubscriptionManager(s.subscriptionOptions, sess.notifyResourceUpdated)
+	sess.unwatchTools = s.watchRegistry(sess)
+	return sess
+}
+
+// send 向客户端发送一条消息
+func (sess *session) send(message any) {
+	if err := sess.sink.write(message); err != nil {
+		sess.logSendError(err)
+	}
+}
+
+// notify 向客户端推送服务器主动发起的通知
+func (sess *session) notify(method string, params any) {
+	sess.send(&types.Notification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
+// notifyRequest 推送与某个请求相关的通知，优先使用该请求专用的消息出口
+func (sess *session) notifyRequest(ctx context.Context, method string, params any) {
+	sink := requestSinkFrom(ctx)
+	if sink == nil {
+		sess.notify(method, params)
+		return
+	}
+
+	if err := sink.write(&types.Notification{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		sess.logSendError(err)
+	}
+}
+
+// logSendError 记录消息发送失败；HTTP 会话没有打开的事件流时推送的消息本就会被丢弃，只在调试级别记录
+func (sess *session) logSendError(err error) {
+	if errors.Is(err, errNoStream) {
+		sess.local.Debug("没有打开的事件流，消息被丢弃")
+		return
+	}
+	sess.local.Warn("发送消息失败", "error", err)
+}
+
+// watchRegistry 监听工具注册表的变化并返回取消监听的函数。工具的注册、注销与启停会改变工具列表，
+// 也可能使依赖这些工具的资源与提示模板出现或消失，按声明的能力推送相应的 list_changed 通知；
+// 资源与提示模板只在列表确实改变时通知。握手完成前客户端尚未获取过列表，无需通知。
+func (s *Server) watchRegistry(sess *session) func() {
+	c := s.capabilities
+	toolsChanged := c.Tools && c.ToolsListChanged
+	resourcesChanged := c.Resources && c.ResourcesListChanged
+	promptsChanged := c.Prompts && c.PromptsListChanged
+	if !toolsChanged && !resourcesChanged && !promptsChanged {
+		return func() {}
+	}
+
+	var mu sync.Mutex
+	resources, prompts := s.resources.signature(), s.prompts.signature()
+	return s.registry.Watch(func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		lastResources, lastPrompts := resources, prompts
+		resources, prompts = s.resources.signature(), s.prompts.signature()
+		if !sess.initialized() {
+			return
+		}
+		if toolsChanged {
+			sess.notify(types.MethodNotificationToolsChanged, nil)
+		}
+		if resourcesChanged && resources != lastResources {
+			sess.notify(types.MethodNotificationResourcesChanged, nil)
+		}
+		if promptsChanged && prompts != lastPrompts {
+			sess.notify(types.MethodNotificationPromptsChanged, nil)
+		}
+	})
+}
+
+// notifyResourceUpdated 通知客户端已订阅的资源发生了变化
+func (sess *session) notifyResourceUpdated(uri string) {
+	sess.notify(types.MethodNotificationResourceUpdated, types.ResourceUpdatedParams{URI: uri})
+}
+
+// request 向客户端发送一个服务器发起的请求并等待响应，直至 ctx 结束
+func (sess *session) request(ctx context.Context, method string, params any) (*types.Response, error) {
+	id := json.RawMessage(strconv.FormatInt(sess.nextID.Add(1), 10))
+	key := requestKey(id)
+	reply := make(chan *types.Response, 1)
+
+	sess.mu.Lock()
+	sess.pending[key] = reply
+	sess.mu.Unlock()
+
+	defer func() {
+		sess.mu.Lock()
+		delete(sess.pending, key)
+		sess.mu.Unlock()
+	}()
+
+	message := &types.Request{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("序列化 %s 请求参数失败: %v", method, err)
+		}
+		message.Params = raw
+	}
+	if err := sess.sink.write(message); err != nil {
+		return nil, fmt.Errorf("发送 %s 请求失败: %v", method, err)
+	}
+
+	select {
+	case response := <-reply:
+		return response, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("等待 %s 响应失败: %w", method, context.Cause(ctx))
+	}
+}
+
+// deliver 将客户端返回的响应交给等待中的 request，未知 ID 的响应直接丢弃
+func (sess *session) deliver(response *types.Response) {
+	key := requestKey(response.ID)
+
+	sess.mu.Lock()
+	reply, exists := sess.pending[key]
+	delete(sess.pending, key)
+	sess.mu.Unlock()
+
+	if exists {
+		reply <- response
+	}
+}
+
+// track 登记一个在途请求并返回其 context，ID 已在处理中时返回 false
+func (sess *session) track(id json.RawMessage) (context.Context, bool) {
+	key := requestKey(id)
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if _, exists := sess.inflight[key]; exists {
+		return nil, false
+	}
+	ctx, cancel := context.WithCancelCause(sess.ctx)
+	sess.inflight[key] = cancel
+	return ctx, true
+}
+
+// untrack 移除已完成的在途请求并释放其 context
+func (sess *session) untrack(id json.RawMessage) {
+	key := requestKey(id)
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if cancel, exists := sess.inflight[key]; exists {
+		cancel(nil)
+		delete(sess.inflight, key)
+	}
+}
+
+// cancelRequest 取消指定 ID 的在途请求，请求不存在（已完成或从未收到）时返回 false
+func (sess *session) cancelRequest(id json.RawMessage) bool {
+	key := requestKey(id)
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	cancel, exists := sess.inflight[key]
+	if exists {
+		cancel(errRequestCancelled)
+	}
+	return exists
+}
+
+// abort 取消会话内的全部在途请求，用于连接断开等无法再回复客户端的场景
+func (sess *session) abort() {
+	sess.cancel()
+}
+
+// close 等待在途请求完成并释放会话持有的后台资源
+func (sess *session) close() {
+	sess.unwatchTools()
+	sess.wg.Wait()
+	sess.cancel()
+	sess.subscriptions.close()
+}
+
+// requestKey 将请求 ID 规范化为 map 键，去除多余空白使同一 ID 的不同写法得到相同的键
+func requestKey(id json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, id); err != nil {
+		return string(id)
+	}
+	return buf.String()
+}
+
+// cancelledByClient 判断 ctx 是否因客户端的取消通知而结束
+func cancelledByClient(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errRequestCancelled)
+}
+
+// newSessionID 生成随机的会话标识
+func newSessionID() string {
+	return rand.Text()
+}
@@ -0,0 +1,120 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// sseHandler 实现 2024-11-05 版本的 HTTP+SSE 传输：
+// 客户端通过 GET ssePath 建立事件流并获得消息端点，之后向
+// POST messagePath?sessionId=... 提交请求，响应经由事件流返回。
+type sseHandler struct {
+	server      *Server
+	ssePath     string
+	messagePath string
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// SSEHandler 返回以旧版 HTTP+SSE 传输提供服务的 http.Handler，
+// 每个事件流对应一个独立会话，多个客户端可同时连接。
+func (s *Server) SSEHandler(ssePath, messagePath string) http.Handler {
+	return &sseHandler{
+		server:      s,
+		ssePath:     ssePath,
+		messagePath: messagePath,
+		sessions:    make(map[string]*session),
+	}
+}
+
+// ListenAndServeSSE 在 addr 上以旧版 HTTP+SSE 传输运行服务器，
+// 事件流端点为 /sse，消息端点为 /messages。
+func (s *Server) ListenAndServeSSE(addr string) error {
+	return s.Serve(context.Background(), SSETransport(addr))
+}
+
+// ServeHTTP 按路径分发事件流与消息请求
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.server.validRequest(r) {
+		http.Error(w, "Forbidden: invalid origin", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.URL.Path == h.ssePath && r.Method == http.MethodGet:
+		h.handleStream(w, r)
+	case r.URL.Path == h.messagePath && r.Method == http.MethodPost:
+		h.handleMessage(w, r)
+	case r.URL.Path == h.ssePath || r.URL.Path == h.messagePath:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleStream 创建会话并保持事件流，连接断开时结束会话
+func (h *sseHandler) handleStream(w http.ResponseWriter, r *http.Request) {
+	sink := &streamSink{}
+	stream, _ := sink.attach()
+	defer sink.detach(stream)
+
+	sess := h.server.newSession(newSessionID(), sink)
+	h.mu.Lock()
+	h.sessions[sess.id] = sess
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.sessions, sess.id)
+		h.mu.Unlock()
+		// 事件流断开后响应已无法送达，取消仍在处理的请求
+		sess.abort()
+		sess.close()
+	}()
+
+	// 客户端不再应答 ping 时关闭事件流，会话随之结束
+	h.server.startKeepAlive(r.Context(), sess, func() { sink.closeWith(r.Context(), nil) })
+
+	endpoint := h.messagePath + "?sessionId=" + url.QueryEscape(sess.id)
+	serveEventStream(w, r, stream, func(w io.Writer) {
+		writeSSEEvent(w, "endpoint", []byte(endpoint))
+	})
+}
+
+// handleMessage 处理客户端提交的 JSON-RPC 消息，响应通过事件流异步返回
+func (h *sseHandler) handleMessage(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("sessionId")
+	if id == "" {
+		http.Error(w, "Missing sessionId", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	sess, exists := h.sessions[id]
+	h.mu.Unlock()
+	if !exists {
+		http.Error(w, "Unknown session", http.StatusNotFound)
+		return
+	}
+
+	body, err := readBody(w, r, h.server.maxMessageSize)
+	if errors.Is(err, errMessageTooLarge) {
+		// 与其他错误一样经由事件流回复，客户端只在事件流上等待响应
+		sess.send(h.server.messageTooLargeResponse())
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	// 请求在后台处理，响应（包括解析与校验错误）经由事件流返回
+	h.server.serveMessage(sess, body)
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,545 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-mcp/mcp/types"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// headerSessionID 为 Streamable HTTP 传输中携带会话标识的请求/响应头。
+	headerSessionID = "Mcp-Session-Id"
+	// headerProtocolVersion 为客户端在握手之后的请求中声明所用协议版本的请求头。
+	headerProtocolVersion = "Mcp-Protocol-Version"
+	// streamBufferSize 为每个 SSE 流缓冲的待发送消息数量。
+	streamBufferSize = 64
+	// defaultSessionIdleTimeout 为 HTTP 会话在没有任何请求使用时保留的默认时长。
+	defaultSessionIdleTimeout = 30 * time.Minute
+)
+
+var (
+	// errNoStream 表示会话当前没有打开的 SSE 流，服务器推送的消息被丢弃。
+	errNoStream = errors.New("no open event stream")
+	// errStreamFull 表示客户端读取过慢，SSE 流缓冲区已满。
+	errStreamFull = errors.New("event stream buffer full")
+)
+
+// streamSink 将服务器推送的消息转发到会话当前打开的 SSE 流。
+type streamSink struct {
+	mu     sync.Mutex
+	stream chan []byte
+}
+
+// write 编码消息并投递到 SSE 流；没有打开的流或缓冲区已满时返回错误。
+func (ss *streamSink) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.stream == nil {
+		return errNoStream
+	}
+	select {
+	case ss.stream <- data:
+		return nil
+	default:
+		return errStreamFull
+	}
+}
+
+// attach 打开一个新的 SSE 流，同一时刻每个会话只允许一个流。
+func (ss *streamSink) attach() (chan []byte, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.stream != nil {
+		return nil, false
+	}
+	ss.stream = make(chan []byte, streamBufferSize)
+	return ss.stream, true
+}
+
+// closeWith 写出最后一条消息后关闭 SSE 流；与 write 不同，它会等待缓冲区空出，
+// 以免最终响应因进度通知过多而丢失，直至 ctx 结束（客户端断开）。message 为 nil 时直接关闭。
+// 消息无法序列化时仍会关闭流，并返回序列化错误。
+func (ss *streamSink) closeWith(ctx context.Context, message any) error {
+	var data []byte
+	var err error
+	if message != nil {
+		if data, err = json.Marshal(message); err != nil {
+			err = fmt.Errorf("序列化消息失败: %v", err)
+		}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.stream == nil {
+		return err
+	}
+	if data != nil {
+		select {
+		case ss.stream <- data:
+		case <-ctx.Done():
+		}
+	}
+	close(ss.stream)
+	ss.stream = nil
+	return err
+}
+
+// detach 关闭当前的 SSE 流。
+func (ss *streamSink) detach(stream chan []byte) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.stream == stream {
+		ss.stream = nil
+	}
+}
+
+// streamableHTTPHandler 实现 MCP Streamable HTTP 传输：
+// POST 提交 JSON-RPC 消息，GET 打开服务器推送的 SSE 流，DELETE 结束会话。
+type streamableHTTPHandler struct {
+	server *Server
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+	// closed 为 true 时服务已关闭，不再登记新会话
+	closed bool
+}
+
+// httpSession 为已登记的 HTTP 会话。客户端可能不发送 DELETE 就离开，
+// 因此没有请求使用的会话在空闲 sessionIdleTimeout 后自动结束。
+type httpSession struct {
+	*session
+	// active 为正在使用会话的 HTTP 请求数（包括打开的事件流），为 0 时才开始计算空闲时间
+	active int
+	idle   *time.Timer
+}
+
+// StreamableHTTPHandler 返回以 Streamable HTTP 传输提供服务的 http.Handler，
+// 与 stdio 传输共享同一套工具、资源与提示模板。
+func (s *Server) StreamableHTTPHandler() http.Handler {
+	return s.streamableHTTPHandler()
+}
+
+func (s *Server) streamableHTTPHandler() *streamableHTTPHandler {
+	return &streamableHTTPHandler{
+		server:   s,
+		sessions: make(map[string]*httpSession),
+	}
+}
+
+// ListenAndServeHTTP 在 addr 上以 Streamable HTTP 传输运行服务器，endpoint 为 MCP 端点路径。
+func (s *Server) ListenAndServeHTTP(addr, endpoint string) error {
+	return s.Serve(context.Background(), HTTPTransport(addr, endpoint))
+}
+
+// ServeHTTP 按 HTTP 方法分发请求
+func (h *streamableHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.server.validRequest(r) {
+		http.Error(w, "Forbidden: invalid origin", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleGet(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost 处理客户端提交的 JSON-RPC 消息
+func (h *streamableHTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(w, r, h.server.maxMessageSize)
+	if errors.Is(err, errMessageTooLarge) {
+		h.writeJSON(w, http.StatusRequestEntityTooLarge, h.server.messageTooLargeResponse())
+		return
+	}
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if isBatch(body) {
+		h.handleBatch(w, r, body)
+		return
+	}
+
+	// 客户端对服务器所发请求的响应无需应答
+	if response, ok := decodeResponse(body); ok {
+		sess, release, status := h.lookupSession(r)
+		if sess == nil {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		defer release()
+		sess.deliver(response)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	req, errResp := decodeMessage(body)
+	if errResp != nil {
+		h.writeJSON(w, http.StatusBadRequest, errResp)
+		return
+	}
+	if req == nil {
+		// 参数无效的通知无需回复
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if req.Method == types.MethodInitialize {
+		h.handleInitialize(w, req)
+		return
+	}
+
+	sess, release, status := h.lookupSession(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	defer release()
+	w.Header().Set(headerSessionID, sess.id)
+
+	// 通知与客户端返回的响应无需应答
+	if req.ID == nil {
+		h.server.handleRequest(sess.ctx, sess, req)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// 客户端接受 SSE 时以事件流返回，使进度等请求相关的通知能在响应之前送达
+	if acceptsEventStream(r) {
+		h.streamResponse(w, r, func(sink messageSink) any {
+			return h.server.processRequest(sess, req, sink)
+		})
+		return
+	}
+
+	// 每个 POST 在独立的 goroutine 中处理，由工作池统一限制并发
+	response := h.server.processRequest(sess, req, nil)
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// handleInitialize 在新会话上处理 initialize，握手成功后才登记会话并返回会话标识，
+// 失败的握手不会留下会话。initialize 不产生进度等通知，因此总是以 JSON 响应。
+func (h *streamableHTTPHandler) handleInitialize(w http.ResponseWriter, req *types.Request) {
+	sess := h.server.newSession(newSessionID(), &streamSink{})
+
+	// 以通知形式发送的 initialize 不会完成握手，处理后即丢弃会话
+	if req.ID == nil {
+		h.server.handleRequest(sess.ctx, sess, req)
+		sess.close()
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	response := h.server.processRequest(sess, req, nil)
+	if response.Error != nil {
+		sess.close()
+		h.writeJSON(w, http.StatusOK, response)
+		return
+	}
+	if !h.register(sess) {
+		sess.close()
+		http.Error(w, "Server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set(headerSessionID, sess.id)
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// handleBatch 处理批量请求；批量请求不能包含 initialize，因此必须属于已有会话
+func (h *streamableHTTPHandler) handleBatch(w http.ResponseWriter, r *http.Request, body []byte) {
+	sess, release, status := h.lookupSession(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	defer release()
+	w.Header().Set(headerSessionID, sess.id)
+
+	if acceptsEventStream(r) {
+		h.streamResponse(w, r, func(sink messageSink) any {
+			return h.server.processBatch(sess, body, sink)
+		})
+		return
+	}
+
+	reply := h.server.processBatch(sess, body, nil)
+	if reply == nil {
+		// 只包含通知的批量请求无需应答
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, reply)
+}
+
+// streamResponse 以 SSE 流返回请求的相关通知及最终响应，响应发出后关闭流
+func (h *streamableHTTPHandler) streamResponse(w http.ResponseWriter, r *http.Request, process func(sink messageSink) any) {
+	sink := &streamSink{}
+	stream, _ := sink.attach()
+
+	go func() {
+		reply := process(sink)
+		if err := sink.closeWith(r.Context(), reply); err != nil {
+			h.server.logger.Error("写出响应失败", "error", err)
+		}
+	}()
+
+	serveEventStream(w, r, stream, nil)
+}
+
+// handleGet 为会话打开服务器推送消息的 SSE 流
+func (h *streamableHTTPHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sess, release, status := h.lookupSession(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	defer release()
+
+	sink := sess.sink.(*streamSink)
+	stream, ok := sink.attach()
+	if !ok {
+		http.Error(w, "Event stream already open", http.StatusConflict)
+		return
+	}
+	defer sink.detach(stream)
+
+	// 事件流打开期间由服务器主动 ping，客户端不再应答时结束整个会话
+	h.server.startKeepAlive(r.Context(), sess, func() { h.terminate(sess.id) })
+
+	serveEventStream(w, r, stream, nil)
+}
+
+// handleDelete 结束会话
+func (h *streamableHTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	sess, release, status := h.lookupSession(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	release()
+
+	h.terminate(sess.id)
+	w.WriteHeader(http.StatusOK)
+}
+
+// register 登记握手成功的会话并开始计算空闲时间；服务已关闭时返回 false
+func (h *streamableHTTPHandler) register(sess *session) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+	entry := &httpSession{session: sess}
+	if timeout := h.server.sessionIdleTimeout; timeout > 0 {
+		entry.idle = time.AfterFunc(timeout, func() { h.expire(entry) })
+	}
+	h.sessions[sess.id] = entry
+	return true
+}
+
+// expire 结束空闲超时的会话；计时器触发时会话可能恰好重新被使用，此时放弃
+func (h *streamableHTTPHandler) expire(entry *httpSession) {
+	h.mu.Lock()
+	if h.sessions[entry.id] != entry || entry.active > 0 {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.sessions, entry.id)
+	h.mu.Unlock()
+
+	entry.local.Info("会话空闲超时，已结束", "idle", h.server.sessionIdleTimeout)
+	closeHTTPSession(entry.session)
+}
+
+// terminate 注销会话，关闭其 SSE 流并取消仍在处理的请求；会话已结束时不做任何事
+func (h *streamableHTTPHandler) terminate(id string) {
+	h.mu.Lock()
+	entry, exists := h.sessions[id]
+	if exists {
+		delete(h.sessions, id)
+		if entry.idle != nil {
+			entry.idle.Stop()
+		}
+	}
+	h.mu.Unlock()
+
+	if exists {
+		closeHTTPSession(entry.session)
+	}
+}
+
+// closeAll 结束全部会话并拒绝之后的 initialize，在服务关闭时调用
+func (h *streamableHTTPHandler) closeAll() {
+	h.mu.Lock()
+	h.closed = true
+	entries := h.sessions
+	h.sessions = make(map[string]*httpSession)
+	h.mu.Unlock()
+
+	for _, entry := range entries {
+		if entry.idle != nil {
+			entry.idle.Stop()
+		}
+		closeHTTPSession(entry.session)
+	}
+}
+
+// closeHTTPSession 关闭会话的 SSE 流，取消仍在处理的请求并释放会话资源
+func closeHTTPSession(sess *session) {
+	sess.sink.(*streamSink).closeWith(context.Background(), nil)
+	sess.abort()
+	sess.close()
+}
+
+// lookupSession 根据请求头查找会话并标记为使用中，请求处理完毕后须调用返回的 release；
+// 失败时返回对应的 HTTP 状态码
+func (h *streamableHTTPHandler) lookupSession(r *http.Request) (*session, func(), int) {
+	id := r.Header.Get(headerSessionID)
+	if id == "" {
+		return nil, nil, http.StatusBadRequest
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry, exists := h.sessions[id]
+	if !exists {
+		return nil, nil, http.StatusNotFound
+	}
+
+	// 客户端声明了不支持的协议版本时按规范返回 400
+	if version := r.Header.Get(headerProtocolVersion); version != "" && !supportedProtocolVersion(version) {
+		return nil, nil, http.StatusBadRequest
+	}
+
+	entry.active++
+	if entry.idle != nil {
+		entry.idle.Stop()
+	}
+	return entry.session, func() { h.release(entry) }, http.StatusOK
+}
+
+// release 结束一次对会话的使用，会话不再被任何请求使用时重新开始计算空闲时间
+func (h *streamableHTTPHandler) release(entry *httpSession) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry.active--
+	if entry.active == 0 && entry.idle != nil && h.sessions[entry.id] == entry {
+		entry.idle.Reset(h.server.sessionIdleTimeout)
+	}
+}
+
+// serveEventStream 将 stream 中的消息以 SSE message 事件写出，直到客户端断开或 stream 关闭。
+// prelude 非空时会在首个消息之前写出（如旧版 SSE 传输的 endpoint 事件）。
+func serveEventStream(w http.ResponseWriter, r *http.Request, stream <-chan []byte, prelude func(w io.Writer)) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if prelude != nil {
+		prelude(w)
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-stream:
+			if !ok {
+				return
+			}
+			writeSSEEvent(w, "message", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// acceptsEventStream 判断客户端是否接受 SSE 响应
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// writeSSEEvent 写出一个 SSE 事件
+func writeSSEEvent(w io.Writer, event string, data []byte) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// writeJSON 以 application/json 写出响应体
+func (h *streamableHTTPHandler) writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		// 通常是客户端已断开
+		h.server.logger.Debug("写出响应失败", "error", err)
+	}
+}
+
+// localHosts 为总是允许的本机主机名。
+var localHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// validRequest 防止 DNS 重绑定攻击：Host 请求头以及 Origin（存在时）的主机名都必须是本机地址
+// 或通过 WithAllowedHosts 允许的主机名。仅检查 Origin 与 Host 是否一致并不够：攻击者控制的
+// 域名重新解析到本机后，浏览器发出的请求中两者恰好相同。
+func (s *Server) validRequest(r *http.Request) bool {
+	if !s.allowedHost((&url.URL{Host: r.Host}).Hostname()) {
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return s.allowedHost(u.Hostname())
+}
+
+// allowedHost 判断主机名（不含端口与 IPv6 的方括号）是否允许访问
+func (s *Server) allowedHost(host string) bool {
+	host = strings.ToLower(host)
+	return slices.Contains(localHosts, host) || slices.Contains(s.allowedHosts, host)
+}
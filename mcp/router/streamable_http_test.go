@@ -0,0 +1,229 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newHTTPTestServer 以 Streamable HTTP 传输启动测试服务器
+func newHTTPTestServer(t *testing.T, opts ...Option) (*httptest.Server, *streamableHTTPHandler) {
+	t.Helper()
+	handler := newTestServer(opts...).streamableHTTPHandler()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		ts.Close()
+		handler.closeAll()
+	})
+	return ts, handler
+}
+
+// post 向 MCP 端点提交一条消息，sessionID 为空时不携带会话标识
+func post(t *testing.T, ts *httptest.Server, sessionID, body string, header ...string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if sessionID != "" {
+		req.Header.Set(headerSessionID, sessionID)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	return do(t, req)
+}
+
+// do 发送请求并读完响应体，响应体仍可通过 decodeBody 解码
+func do(t *testing.T, req *http.Request) *http.Response {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body = io.NopCloser(strings.NewReader(string(body)))
+	return resp
+}
+
+// decodeBody 将 JSON 响应体解码为 testResponse
+func decodeBody(t *testing.T, resp *http.Response) testResponse {
+	t.Helper()
+	var response testResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+	return response
+}
+
+// expectStatus 断言响应的 HTTP 状态码
+func expectStatus(t *testing.T, resp *http.Response, status int) {
+	t.Helper()
+	if resp.StatusCode != status {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, status)
+	}
+}
+
+// initialize 完成握手并返回会话标识
+func initialize(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+	resp := post(t, ts, "", initializeLine)
+	expectStatus(t, resp, http.StatusOK)
+	if response := decodeBody(t, resp); response.Error != nil {
+		t.Fatalf("initialize failed: %d %s", response.Error.Code, response.Error.Message)
+	}
+	id := resp.Header.Get(headerSessionID)
+	if id == "" {
+		t.Fatal("initialize response has no session id")
+	}
+	expectStatus(t, post(t, ts, id, initializedLine), http.StatusAccepted)
+	return id
+}
+
+func TestHTTPInitializeReturnsSessionID(t *testing.T) {
+	ts, _ := newHTTPTestServer(t)
+	id := initialize(t, ts)
+
+	resp := post(t, ts, id, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	expectStatus(t, resp, http.StatusOK)
+	if got := resp.Header.Get(headerSessionID); got != id {
+		t.Errorf("session id = %q, want %q", got, id)
+	}
+	if response := decodeBody(t, resp); response.Error != nil || string(response.ID) != "1" {
+		t.Errorf("tools/list response = %+v", response)
+	}
+}
+
+func TestHTTPFailedInitializeCreatesNoSession(t *testing.T) {
+	ts, handler := newHTTPTestServer(t)
+
+	resp := post(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":"none"}}`)
+	expectStatus(t, resp, http.StatusOK)
+	if response := decodeBody(t, resp); response.Error == nil {
+		t.Fatalf("initialize with invalid params succeeded: %s", response.Result)
+	}
+	if id := resp.Header.Get(headerSessionID); id != "" {
+		t.Errorf("failed initialize returned session id %q", id)
+	}
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if len(handler.sessions) != 0 {
+		t.Errorf("%d sessions registered after failed initialize, want 0", len(handler.sessions))
+	}
+}
+
+func TestHTTPNotificationAccepted(t *testing.T) {
+	ts, _ := newHTTPTestServer(t)
+	id := initialize(t, ts)
+
+	resp := post(t, ts, id, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":9}}`)
+	expectStatus(t, resp, http.StatusAccepted)
+	if body, _ := io.ReadAll(resp.Body); len(body) != 0 {
+		t.Errorf("notification got body %q", body)
+	}
+}
+
+func TestHTTPMissingSessionID(t *testing.T) {
+	ts, _ := newHTTPTestServer(t)
+	initialize(t, ts)
+
+	expectStatus(t, post(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`), http.StatusBadRequest)
+}
+
+func TestHTTPUnknownSession(t *testing.T) {
+	ts, _ := newHTTPTestServer(t)
+
+	expectStatus(t, post(t, ts, "no-such-session", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`), http.StatusNotFound)
+}
+
+func TestHTTPDeleteEndsSession(t *testing.T) {
+	ts, _ := newHTTPTestServer(t)
+	id := initialize(t, ts)
+
+	req, err := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(headerSessionID, id)
+	expectStatus(t, do(t, req), http.StatusOK)
+
+	expectStatus(t, post(t, ts, id, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`), http.StatusNotFound)
+	expectStatus(t, do(t, req), http.StatusNotFound)
+}
+
+func TestHTTPRejectsForeignOrigin(t *testing.T) {
+	ts, _ := newHTTPTestServer(t)
+
+	tests := []struct {
+		name   string
+		header []string
+		status int
+	}{
+		{"foreign origin", []string{"Origin", "http://evil.example"}, http.StatusForbidden},
+		{"local origin", []string{"Origin", "http://localhost:3000"}, http.StatusOK},
+		{"ipv6 loopback origin", []string{"Origin", "http://[::1]:3000"}, http.StatusOK},
+		{"malformed origin", []string{"Origin", "http://%zz"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, post(t, ts, "", initializeLine, tt.header...), tt.status)
+		})
+	}
+}
+
+func TestHTTPRejectsForeignHost(t *testing.T) {
+	// DNS 重绑定后浏览器以攻击者的域名作为 Host 与 Origin，两者一致也必须拒绝
+	rebind := func(t *testing.T, ts *httptest.Server) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(initializeLine))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = "evil.example"
+		req.Header.Set("Origin", "http://evil.example")
+		return do(t, req)
+	}
+
+	ts, _ := newHTTPTestServer(t)
+	expectStatus(t, rebind(t, ts), http.StatusForbidden)
+
+	allowed, _ := newHTTPTestServer(t, WithAllowedHosts("EVIL.example"))
+	expectStatus(t, rebind(t, allowed), http.StatusOK)
+}
+
+func TestHTTPIdleSessionExpires(t *testing.T) {
+	ts, _ := newHTTPTestServer(t, WithSessionIdleTimeout(50*time.Millisecond))
+	id := initialize(t, ts)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp := post(t, ts, id, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		if resp.StatusCode == http.StatusNotFound {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("session still alive after idle timeout, status = %d", resp.StatusCode)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestHTTPCloseAllEndsSessions(t *testing.T) {
+	ts, handler := newHTTPTestServer(t)
+	id := initialize(t, ts)
+
+	handler.closeAll()
+
+	expectStatus(t, post(t, ts, id, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`), http.StatusNotFound)
+	expectStatus(t, post(t, ts, "", initializeLine), http.StatusServiceUnavailable)
+}
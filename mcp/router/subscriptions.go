@@ -0,0 +1,135 @@
+package router
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// SubscriptionOptions 控制资源订阅的后台采样与推送策略。
+type SubscriptionOptions struct {
+	// Interval 为后台采样周期。
+	Interval time.Duration
+	// MinDelta 为触发推送的最小指标变化量（百分点），如内存使用率、CPU 使用率。
+	MinDelta float64
+	// MaxInterval 为两次推送之间的最长间隔，即使指标变化未达到 MinDelta 也会推送；
+	// 为 0 时仅按 MinDelta 推送，没有关键指标的资源也将不再推送。
+	MaxInterval time.Duration
+}
+
+// DefaultSubscriptionOptions 返回默认的订阅采样策略。
+func DefaultSubscriptionOptions() SubscriptionOptions {
+	return SubscriptionOptions{
+		Interval:    5 * time.Second,
+		MinDelta:    5,
+		MaxInterval: time.Minute,
+	}
+}
+
+// subscriptionManager 维护已订阅的资源，并为每个资源运行一个后台采样器。
+type subscriptionManager struct {
+	mu     sync.Mutex
+	opts   SubscriptionOptions
+	notify func(uri string)
+	active map[string]context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newSubscriptionManager 创建订阅管理器，notify 在资源需要推送更新时被调用。
+func newSubscriptionManager(opts SubscriptionOptions, notify func(uri string)) *subscriptionManager {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultSubscriptionOptions().Interval
+	}
+	return &subscriptionManager{
+		opts:   opts,
+		notify: notify,
+		active: make(map[string]context.CancelFunc),
+	}
+}
+
+// subscribe 开始采样指定资源；重复订阅同一 URI 不会启动新的采样器。
+func (m *subscriptionManager) subscribe(uri string, read func(ctx context.Context) (any, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.active[uri]; exists {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.active[uri] = cancel
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.watch(ctx, uri, read)
+	}()
+}
+
+// unsubscribe 停止采样指定资源，返回该资源此前是否处于订阅状态。
+func (m *subscriptionManager) unsubscribe(uri string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cancel, exists := m.active[uri]
+	if !exists {
+		return false
+	}
+	cancel()
+	delete(m.active, uri)
+	return true
+}
+
+// close 停止所有采样器并等待其退出。
+func (m *subscriptionManager) close() {
+	m.mu.Lock()
+	for uri, cancel := range m.active {
+		cancel()
+		delete(m.active, uri)
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// watch 周期性采样资源，在关键指标变化超过阈值或超过最长间隔时推送更新通知。
+func (m *subscriptionManager) watch(ctx context.Context, uri string, read func(ctx context.Context) (any, error)) {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	// 以订阅时刻的数据作为比较基准
+	baseline, hasBaseline := math.NaN(), false
+	if data, err := sample(ctx, read); err == nil {
+		baseline, hasBaseline = resourceMetric(data)
+	}
+	lastNotified := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		data, err := sample(ctx, read)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			// 采样失败（包括采集过程中发生 panic）时跳过本轮，等待下一周期
+			continue
+		}
+
+		metric, hasMetric := resourceMetric(data)
+		changed := hasMetric && (!hasBaseline || math.Abs(metric-baseline) >= m.opts.MinDelta)
+		expired := m.opts.MaxInterval > 0 && time.Since(lastNotified) >= m.opts.MaxInterval
+		if !changed && !expired {
+			continue
+		}
+
+		m.notify(uri)
+		baseline, hasBaseline = metric, hasMetric
+		lastNotified = time.Now()
+	}
+}
@@ -0,0 +1,146 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// shutdownTimeout 为 ctx 取消后等待 HTTP 连接处理完毕的最长时间。
+const shutdownTimeout = 5 * time.Second
+
+// Transport 表示服务器对外提供服务的一种传输方式，由 StdioTransport、HTTPTransport、
+// SSETransport 或 WebSocketTransport 创建，交给 Server.Serve 运行。
+// 需要把 MCP 挂到已有 HTTP 服务上的应用可以直接使用 StreamableHTTPHandler 等 http.Handler。
+type Transport interface {
+	serve(ctx context.Context, s *Server) error
+}
+
+// transportFunc 将函数适配为 Transport
+type transportFunc func(ctx context.Context, s *Server) error
+
+func (f transportFunc) serve(ctx context.Context, s *Server) error {
+	return f(ctx, s)
+}
+
+// StdioTransport 通过 WithIO 指定的输入输出（默认为标准输入输出）逐行收发 JSON-RPC 消息。
+func StdioTransport() Transport {
+	return transportFunc(func(ctx context.Context, s *Server) error {
+		return s.serveStdio(ctx)
+	})
+}
+
+// HTTPTransport 在 addr 上以 Streamable HTTP 传输提供服务，MCP 端点为 endpoint。
+func HTTPTransport(addr, endpoint string) Transport {
+	return transportFunc(func(ctx context.Context, s *Server) error {
+		handler := s.streamableHTTPHandler()
+		// 客户端不一定会发送 DELETE，服务关闭后结束仍然登记着的会话
+		defer handler.closeAll()
+
+		mux := http.NewServeMux()
+		mux.Handle(endpoint, handler)
+		return s.serveHTTP(ctx, addr, mux)
+	})
+}
+
+// SSETransport 在 addr 上以旧版 HTTP+SSE 传输提供服务，事件流端点为 /sse，消息端点为 /messages。
+func SSETransport(addr string) Transport {
+	return transportFunc(func(ctx context.Context, s *Server) error {
+		return s.serveHTTP(ctx, addr, s.SSEHandler("/sse", "/messages"))
+	})
+}
+
+// WebSocketTransport 在 addr 上以 WebSocket 传输提供服务，MCP 端点为 endpoint。
+func WebSocketTransport(addr, endpoint string) Transport {
+	return transportFunc(func(ctx context.Context, s *Server) error {
+		handler := s.webSocketHandler()
+		// 已升级的连接脱离了 http.Server 的管理，Shutdown 不会关闭它们
+		defer handler.closeAll()
+
+		mux := http.NewServeMux()
+		mux.Handle(endpoint, handler)
+		return s.serveHTTP(ctx, addr, mux)
+	})
+}
+
+// Serve 以指定的传输方式运行服务器，直至传输结束或 ctx 被取消；
+// ctx 被取消时返回 ctx.Err()，即 context.Canceled 或 context.DeadlineExceeded。
+// 同一个 Server 只能运行一次。
+func (s *Server) Serve(ctx context.Context, transport Transport) error {
+	if s.initialized {
+		return fmt.Errorf("路由器已经在运行")
+	}
+	s.initialized = true
+
+	s.logger.Info("MCP 服务器启动", "name", s.info.Name, "version", s.info.Version)
+	err := transport.serve(ctx, s)
+	s.logger.Info("MCP 服务器停止", "error", err)
+	return err
+}
+
+// serveStdio 在单个会话上处理输入流中的消息。返回前停止处理新消息，等待在途请求结束并停止写出，
+// 因此返回后不会再向输出流写入任何内容。阻塞在输入流上的读取无法中断，读到的消息会被丢弃；
+// 嵌入方可以在 Serve 返回后关闭自己的输入流以结束这次读取。
+func (s *Server) serveStdio(ctx context.Context) error {
+	// stdio 只有一个会话，响应与订阅推送共用同一个串行写入器
+	writer := newMessageWriter(s.output)
+	sess := s.newSession("", writer)
+	gate := &dispatchGate{}
+
+	expired := make(chan struct{})
+	s.startKeepAlive(sess.ctx, sess, func() { close(expired) })
+
+	// 启动消息处理循环；读取会阻塞在输入流上，因此放在后台以便 ping 超时或 ctx 取消时返回
+	done := make(chan error, 1)
+	go func() {
+		done <- s.dispatch(sess, gate)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+		// 输入结束后仍等待在途请求完成，使其响应能够写出
+	case <-expired:
+		sess.abort()
+		err = errKeepAliveTimeout
+	case <-ctx.Done():
+		sess.abort()
+		err = ctx.Err()
+	}
+
+	gate.stop()
+	sess.close()
+	writer.close()
+	return err
+}
+
+// serveHTTP 在 addr 上运行 HTTP 服务，ctx 取消时关闭监听并等待请求处理完毕
+func (s *Server) serveHTTP(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+		// 请求 context 继承 ctx，取消时事件流等长连接随之结束
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("关闭 HTTP 服务失败: %v", err)
+		}
+		srv.Close()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// syncBuffer 是可并发写入的 bytes.Buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestStdioStopsOnCancel(t *testing.T) {
+	input, feed := io.Pipe()
+	defer feed.Close()
+	var output syncBuffer
+	server := newTestServer(WithIO(input, &output))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ctx, StdioTransport())
+	}()
+
+	if _, err := io.WriteString(feed, initializeLine+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); !strings.Contains(output.String(), `"init"`); {
+		if time.Now().After(deadline) {
+			t.Fatal("no initialize response")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	select {
+	case err := <-served:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Serve = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after cancel")
+	}
+
+	// 返回后读取循环可能仍阻塞在输入流上，但读到的消息不再处理，也不再写出任何内容
+	before := output.String()
+	if _, err := io.WriteString(feed, `{"jsonrpc":"2.0","id":2,"method":"ping"}`+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if after := output.String(); after != before {
+		t.Errorf("output written after Serve returned: %q", strings.TrimPrefix(after, before))
+	}
+}
+
+func TestWebSocketCloseAll(t *testing.T) {
+	handler := newTestServer().webSocketHandler()
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(initializeLine)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn.ReadMessage(); err != nil {
+		t.Fatalf("read initialize response: %v", err)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		handler.closeAll()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("closeAll did not return")
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("read after closeAll = %v, want close 1001", err)
+	}
+}
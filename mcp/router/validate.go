@@ -0,0 +1,128 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-mcp/mcp/types"
+)
+
+// decodeMessage 解析并校验单条 JSON-RPC 消息的信封结构。
+// 校验失败时返回应回复给客户端的错误响应；无法确定请求 ID 时响应使用 null ID。
+// 格式正确（jsonrpc 与 method 有效）但参数无效的通知无需回复，此时两个返回值均为 nil。
+func decodeMessage(data []byte) (*types.Request, *types.Response) {
+	if !json.Valid(data) {
+		return nil, invalidMessage(nullID, types.CodeParseError, "Parse error: invalid JSON", types.ErrorData{})
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, invalidMessage(nullID, types.CodeInvalidRequest, "Invalid Request: message must be a JSON object",
+			types.ErrorData{Expected: "object"})
+	}
+
+	// 先校验 ID，之后的错误才能带上请求 ID 回复
+	id, hasID := fields["id"]
+	if hasID && !validID(id) {
+		return nil, invalidMessage(nullID, types.CodeInvalidRequest, "Invalid Request: id must be a string or a number",
+			types.ErrorData{Field: "id", Expected: "string | number"})
+	}
+	// 回复错误时 ID 必须存在，无法确定时为 null
+	replyID := id
+	if !hasID {
+		id, replyID = nil, nullID
+	}
+
+	var version string
+	if err := json.Unmarshal(fields["jsonrpc"], &version); err != nil || version != types.JSONRPCVersion {
+		return nil, invalidMessage(replyID, types.CodeInvalidRequest, `Invalid Request: jsonrpc must be "2.0"`,
+			types.ErrorData{Field: "jsonrpc", Expected: types.JSONRPCVersion})
+	}
+
+	var method string
+	if err := json.Unmarshal(fields["method"], &method); err != nil || method == "" {
+		return nil, invalidMessage(replyID, types.CodeInvalidRequest, "Invalid Request: method must be a non-empty string",
+			types.ErrorData{Field: "method", Expected: "string"})
+	}
+
+	params, hasParams := fields["params"]
+	if hasParams && !isStructured(params) {
+		if !hasID {
+			// 通知不能回复，即使参数无效
+			return nil, nil
+		}
+		return nil, invalidMessage(replyID, types.CodeInvalidRequest, "Invalid Request: params must be an object or an array",
+			types.ErrorData{Field: "params", Method: method, Expected: "object | array"})
+	}
+
+	req := &types.Request{
+		JSONRPC: version,
+		ID:      id,
+		Method:  method,
+	}
+	if hasParams && !isNull(params) {
+		req.Params = params
+	}
+	return req, nil
+}
+
+// decodeResponse 识别客户端对服务器所发请求的响应（有 id 与 result/error、没有 method），
+// 不是响应时返回 false，交由 decodeMessage 按请求处理
+func decodeResponse(data []byte) (*types.Response, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, false
+	}
+	if _, hasMethod := fields["method"]; hasMethod {
+		return nil, false
+	}
+	id, hasID := fields["id"]
+	_, hasResult := fields["result"]
+	_, hasError := fields["error"]
+	if !hasID || !validID(id) || hasResult == hasError {
+		return nil, false
+	}
+
+	var response types.Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, false
+	}
+	return &response, true
+}
+
+// invalidMessage 构造信封校验失败时的错误响应
+func invalidMessage(id json.RawMessage, code int, message string, data types.ErrorData) *types.Response {
+	e := types.NewError(code, message)
+	if data != (types.ErrorData{}) {
+		e.WithData(data)
+	}
+	return &types.Response{
+		JSONRPC: types.JSONRPCVersion,
+		ID:      id,
+		Error:   e,
+	}
+}
+
+// validID 判断 ID 是否为 MCP 允许的字符串或数字（MCP 不允许 null ID）
+func validID(id json.RawMessage) bool {
+	var value any
+	if err := json.Unmarshal(id, &value); err != nil {
+		return false
+	}
+	switch value.(type) {
+	case string, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isStructured 判断参数是否为 JSON 对象、数组或 null
+func isStructured(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[' || isNull(trimmed))
+}
+
+// isNull 判断原始 JSON 值是否为 null
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
@@ -0,0 +1,288 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/types"
+	"strings"
+	"testing"
+)
+
+// echoTool 是测试用的工具，原样返回收到的参数个数
+type echoTool struct{}
+
+func (echoTool) GetName() string                     { return "echo" }
+func (echoTool) GetDescription(i18n.Locale) string   { return "echo" }
+func (echoTool) GetOutputSchema() types.OutputSchema { return types.OutputSchema{Type: "object"} }
+func (echoTool) GetAnnotations(i18n.Locale) types.ToolAnnotations {
+	return types.ToolAnnotations{}
+}
+func (echoTool) GetInputSchema(i18n.Locale) types.InputSchema {
+	return types.InputSchema{Type: "object", Properties: map[string]types.Property{}}
+}
+func (echoTool) Execute(ctx context.Context, args map[string]interface{}) (types.ToolOutput, error) {
+	return types.ToolOutput{Text: "ok", Data: map[string]any{"args": len(args)}}, nil
+}
+
+// testResponse 是解码后的响应，ID 保留原始 JSON：null 为 "null"，缺失时为空
+type testResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    types.ErrorData `json:"data"`
+	} `json:"error"`
+}
+
+const (
+	initializeLine  = `{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+	initializedLine = `{"jsonrpc":"2.0","method":"notifications/initialized"}`
+)
+
+// newTestServer 创建只注册了 echo 工具的服务器
+func newTestServer(opts ...Option) *Server {
+	return NewServer(append([]Option{WithTools(echoTool{})}, opts...)...)
+}
+
+// runStdio 以 stdio 传输处理输入的每一行，返回全部输出消息
+func runStdio(t *testing.T, lines ...string) []testResponse {
+	t.Helper()
+	return runServer(t, newTestServer, lines...)
+}
+
+// runServer 以 newServer 创建的服务器处理输入的每一行，返回全部输出消息
+func runServer(t *testing.T, newServer func(...Option) *Server, lines ...string) []testResponse {
+	t.Helper()
+
+	var responses []testResponse
+	for _, line := range runLines(t, newServer, lines...) {
+		var response testResponse
+		if err := json.Unmarshal([]byte(line), &response); err != nil {
+			t.Fatalf("invalid output %q: %v", line, err)
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// runLines 以 newServer 创建的服务器处理输入的每一行，返回未解码的输出行，供批量响应等非单条消息的输出使用
+func runLines(t *testing.T, newServer func(...Option) *Server, lines ...string) []string {
+	t.Helper()
+
+	var out bytes.Buffer
+	server := newServer(WithIO(strings.NewReader(strings.Join(lines, "\n")+"\n"), &out))
+	if err := server.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var output []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line != "" {
+			output = append(output, line)
+		}
+	}
+	return output
+}
+
+// runInitialized 在完成握手后处理输入，返回除 initialize 响应外的全部输出
+func runInitialized(t *testing.T, lines ...string) []testResponse {
+	t.Helper()
+
+	responses := runStdio(t, append([]string{initializeLine, initializedLine}, lines...)...)
+	var rest []testResponse
+	for _, response := range responses {
+		if string(response.ID) == `"init"` {
+			continue
+		}
+		rest = append(rest, response)
+	}
+	return rest
+}
+
+// single 断言只有一条输出并返回它
+func single(t *testing.T, responses []testResponse) testResponse {
+	t.Helper()
+	if len(responses) != 1 {
+		t.Fatalf("got %d messages, want 1: %+v", len(responses), responses)
+	}
+	return responses[0]
+}
+
+// expectError 断言响应为指定错误码，ID 的原始 JSON 为 id
+func expectError(t *testing.T, response testResponse, code int, id string) {
+	t.Helper()
+	if response.JSONRPC != "2.0" {
+		t.Errorf("jsonrpc = %q, want 2.0", response.JSONRPC)
+	}
+	if len(response.ID) == 0 {
+		t.Fatalf("response has no id member, want %s", id)
+	}
+	if got := string(response.ID); got != id {
+		t.Errorf("id = %s, want %s", got, id)
+	}
+	if response.Error == nil {
+		t.Fatalf("response has no error, result = %s", response.Result)
+	}
+	if response.Error.Code != code {
+		t.Errorf("error.code = %d, want %d (%s)", response.Error.Code, code, response.Error.Message)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	response := single(t, runStdio(t, `{"jsonrpc":"2.0","id":1,"method":`))
+	expectError(t, response, types.CodeParseError, "null")
+}
+
+func TestInvalidEnvelope(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		id    string
+		field string
+	}{
+		{"missing jsonrpc", `{"id":1,"method":"ping"}`, "1", "jsonrpc"},
+		{"wrong jsonrpc", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, "1", "jsonrpc"},
+		{"missing id and jsonrpc", `{"method":"foo"}`, "null", "jsonrpc"},
+		{"missing method", `{"jsonrpc":"2.0","id":"a"}`, `"a"`, "method"},
+		{"scalar params", `{"jsonrpc":"2.0","id":2,"method":"ping","params":5}`, "2", "params"},
+		{"not an object", `1`, "null", ""},
+		{"string message", `"ping"`, "null", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := single(t, runStdio(t, tt.line))
+			expectError(t, response, types.CodeInvalidRequest, tt.id)
+			if response.Error.Data.Field != tt.field {
+				t.Errorf("error.data.field = %q, want %q", response.Error.Data.Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestInvalidID(t *testing.T) {
+	for _, id := range []string{"true", "null", "{}", "[1]"} {
+		t.Run(id, func(t *testing.T) {
+			response := single(t, runStdio(t, `{"jsonrpc":"2.0","id":`+id+`,"method":"ping"}`))
+			expectError(t, response, types.CodeInvalidRequest, "null")
+			if response.Error.Data.Field != "id" {
+				t.Errorf("error.data.field = %q, want id", response.Error.Data.Field)
+			}
+		})
+	}
+}
+
+func TestInvalidNotificationIsNotAnswered(t *testing.T) {
+	if responses := runStdio(t, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":5}`); len(responses) != 0 {
+		t.Fatalf("got %d messages for a notification, want none: %+v", len(responses), responses)
+	}
+}
+
+func TestMethodNotFound(t *testing.T) {
+	response := single(t, runInitialized(t, `{"jsonrpc":"2.0","id":7,"method":"no/such/method"}`))
+	expectError(t, response, types.CodeMethodNotFound, "7")
+	if response.Error.Data.Method != "no/such/method" {
+		t.Errorf("error.data.method = %q, want no/such/method", response.Error.Data.Method)
+	}
+}
+
+func TestUnknownTool(t *testing.T) {
+	response := single(t, runInitialized(t, `{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"name":"missing"}}`))
+	expectError(t, response, types.CodeToolError, `"call"`)
+	if want := (types.ErrorData{Field: "name", Tool: "missing"}); response.Error.Data != want {
+		t.Errorf("error.data = %+v, want %+v", response.Error.Data, want)
+	}
+}
+
+func TestErrorDataShape(t *testing.T) {
+	response := single(t, runInitialized(t, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{}}`))
+	expectError(t, response, types.CodeInvalidParams, "1")
+
+	// data 为对象，只包含非空字段
+	var raw struct {
+		Error struct {
+			Data map[string]any `json:"data"`
+		} `json:"error"`
+	}
+	data, _ := json.Marshal(response)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"field": "name", "method": "tools/call"}
+	if len(raw.Error.Data) != len(want) {
+		t.Fatalf("error.data = %v, want %v", raw.Error.Data, want)
+	}
+	for key, value := range want {
+		if raw.Error.Data[key] != value {
+			t.Errorf("error.data.%s = %v, want %v", key, raw.Error.Data[key], value)
+		}
+	}
+}
+
+func TestMetaAllowsExtensionKeys(t *testing.T) {
+	responses := runInitialized(t,
+		`{"jsonrpc":"2.0","id":"list","method":"tools/list","params":{"_meta":{"x":1}}}`,
+		`{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"name":"echo","_meta":{"progressToken":"a","com.example/trace":"x"}}}`,
+	)
+
+	got := make(map[string]testResponse)
+	for _, response := range responses {
+		got[string(response.ID)] = response
+	}
+	for _, id := range []string{`"list"`, `"call"`} {
+		response, ok := got[id]
+		if !ok {
+			t.Fatalf("no response for %s", id)
+		}
+		if response.Error != nil {
+			t.Errorf("%s: unexpected error %d %s", id, response.Error.Code, response.Error.Message)
+		}
+	}
+}
+
+func TestRequestMetaRoundTrip(t *testing.T) {
+	var meta types.RequestMeta
+	if err := json.Unmarshal([]byte(`{"progressToken":7,"locale":"en","com.example/trace":"x"}`), &meta); err != nil {
+		t.Fatal(err)
+	}
+	if string(meta.ProgressToken) != "7" || meta.Locale != "en" {
+		t.Errorf("meta = %+v", meta)
+	}
+	if string(meta.Extra["com.example/trace"]) != `"x"` {
+		t.Errorf("extra = %v", meta.Extra)
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"com.example/trace":"x","locale":"en","progressToken":7}`; string(data) != want {
+		t.Errorf("marshal = %s, want %s", data, want)
+	}
+}
+
+func TestRequestBeforeInitialize(t *testing.T) {
+	// 握手前的请求在收到时即被拒绝，即使 initialize 紧随其后、先于该请求执行完毕
+	for i := 0; i < 20; i++ {
+		responses := runStdio(t, `{"jsonrpc":"2.0","id":0,"method":"tools/list"}`, initializeLine)
+
+		got := make(map[string]testResponse)
+		for _, response := range responses {
+			got[string(response.ID)] = response
+		}
+		response, ok := got["0"]
+		if !ok {
+			t.Fatalf("no response for the early request: %+v", responses)
+		}
+		expectError(t, response, types.CodeInvalidRequest, "0")
+		if response.Error.Data.Expected != "initialize" {
+			t.Errorf("error.data.expected = %q, want initialize", response.Error.Data.Expected)
+		}
+		if init, ok := got[`"init"`]; !ok || init.Error != nil {
+			t.Fatalf("initialize failed: %+v", init)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsWriteWait 为单次写出消息的超时时间。
+	wsWriteWait = 10 * time.Second
+	// wsPongWait 为等待客户端 pong 的最长时间，超时即视为连接失效。
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod 为服务器发送 ping 的周期，必须小于 wsPongWait。
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+// wsSink 将消息以 WebSocket 文本帧写出，每帧一条 JSON-RPC 消息。
+type wsSink struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+// write 编码消息并写出一个文本帧；WebSocket 连接不支持并发写，因此需要加锁。
+func (ws *wsSink) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := ws.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return fmt.Errorf("写出消息失败: %v", err)
+	}
+	return nil
+}
+
+// webSocketHandler 实现基于 WebSocket 的 JSON-RPC 传输，每个连接对应一个会话。
+type webSocketHandler struct {
+	server   *Server
+	upgrader websocket.Upgrader
+
+	// conns 记录已升级的连接。连接被劫持后不再受 http.Server 管理，需要在服务关闭时自行关闭，
+	// wg 用于等待各连接的处理结束
+	mu     sync.Mutex
+	conns  map[*websocket.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// WebSocketHandler 返回以 WebSocket 传输提供服务的 http.Handler，供浏览器端直接访问。
+func (s *Server) WebSocketHandler() http.Handler {
+	return s.webSocketHandler()
+}
+
+func (s *Server) webSocketHandler() *webSocketHandler {
+	return &webSocketHandler{
+		server: s,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: s.validRequest,
+		},
+		conns: make(map[*websocket.Conn]struct{}),
+	}
+}
+
+// ListenAndServeWebSocket 在 addr 上以 WebSocket 传输运行服务器，endpoint 为升级端点路径。
+func (s *Server) ListenAndServeWebSocket(addr, endpoint string) error {
+	return s.Serve(context.Background(), WebSocketTransport(addr, endpoint))
+}
+
+// ServeHTTP 升级连接并处理消息直至连接关闭
+func (h *webSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade 已向客户端写出错误响应
+		return
+	}
+	defer conn.Close()
+
+	if !h.track(conn) {
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+			time.Now().Add(wsWriteWait))
+		return
+	}
+	defer h.untrack(conn)
+
+	sink := &wsSink{conn: conn}
+	sess := h.server.newSession(newSessionID(), sink)
+	defer func() {
+		// 连接断开后响应已无法送达，取消仍在处理的请求
+		sess.abort()
+		sess.close()
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	go keepAlive(conn, done)
+
+	// 客户端不再应答 MCP ping 时关闭连接，读取循环随之退出
+	h.server.startKeepAlive(sess.ctx, sess, func() { conn.Close() })
+
+	for {
+		messageType, data, err := h.readMessage(conn)
+		if errors.Is(err, errMessageTooLarge) {
+			// 超长消息已被丢弃，连接仍可继续使用
+			sess.send(h.server.messageTooLargeResponse())
+			continue
+		}
+		if err != nil {
+			// 客户端关闭连接或 pong 超时
+			return
+		}
+		if messageType != websocket.TextMessage {
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseUnsupportedData, "text frames only"),
+				time.Now().Add(wsWriteWait))
+			return
+		}
+
+		h.server.serveMessage(sess, data)
+	}
+}
+
+// track 登记已升级的连接，服务已关闭时返回 false
+func (h *webSocketHandler) track(conn *websocket.Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+	h.conns[conn] = struct{}{}
+	h.wg.Add(1)
+	return true
+}
+
+// untrack 在连接处理结束、会话关闭后注销连接
+func (h *webSocketHandler) untrack(conn *websocket.Conn) {
+	h.mu.Lock()
+	delete(h.conns, conn)
+	h.mu.Unlock()
+
+	h.wg.Done()
+}
+
+// closeAll 通知客户端服务器即将关闭并断开全部连接，等待各连接的会话关闭后返回
+func (h *webSocketHandler) closeAll() {
+	h.mu.Lock()
+	h.closed = true
+	conns := make([]*websocket.Conn, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+			time.Now().Add(wsWriteWait))
+		conn.Close()
+	}
+	h.wg.Wait()
+}
+
+// readMessage 读取一条消息，超过大小上限时丢弃剩余部分并返回 errMessageTooLarge。
+// 不使用 conn.SetReadLimit，因为超限时 gorilla/websocket 会直接关闭连接，无法回复 JSON-RPC 错误。
+func (h *webSocketHandler) readMessage(conn *websocket.Conn) (int, []byte, error) {
+	messageType, r, err := conn.NextReader()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	limit := h.server.maxMessageSize
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return 0, nil, err
+	}
+	if int64(len(data)) > limit {
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return 0, nil, err
+		}
+		return messageType, nil, errMessageTooLarge
+	}
+	return messageType, data, nil
+}
+
+// keepAlive 周期性发送 ping，直至 done 关闭或发送失败
+func keepAlive(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// errWriterClosed 表示传输已经停止，消息不再写出。
+var errWriterClosed = errors.New("output closed")
+
+// messageWriter 串行化写出 JSON-RPC 消息，保证响应与服务器主动推送的通知
+// 在同一输出流上逐行完整输出，不会相互交错。
+type messageWriter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closed bool
+}
+
+// newMessageWriter 创建绑定到指定输出流的消息写入器。
+func newMessageWriter(out io.Writer) *messageWriter {
+	return &messageWriter{out: out}
+}
+
+// write 将消息编码为单行 JSON 并写出。
+func (w *messageWriter) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+	data = append(data, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return errWriterClosed
+	}
+	if _, err := w.out.Write(data); err != nil {
+		return fmt.Errorf("写出消息失败: %v", err)
+	}
+	return nil
+}
+
+// close 停止写出，之后的消息均被丢弃；输出流归调用方所有，不会被关闭。
+func (w *messageWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+}
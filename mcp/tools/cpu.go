@@ -1,8 +1,11 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"github.com/shirou/gopsutil/v3/cpu"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
 	"go-mcp/mcp/types"
 	"runtime"
 	"time"
@@ -11,6 +14,11 @@ import (
 // CPUTool CPU 监控工具
 type CPUTool struct{}
 
+// cpuArguments 为 cpu_info 的参数
+type cpuArguments struct {
+	Duration string `json:"duration"`
+}
+
 // NewCPUTool 创建新的 CPU 监控工具
 func NewCPUTool() *CPUTool {
 	return &CPUTool{}
@@ -22,45 +30,65 @@ func (ct *CPUTool) GetName() string {
 }
 
 // GetDescription 获取工具描述
-func (ct *CPUTool) GetDescription() string {
-	return "获取 CPU 使用率和详细信息"
+func (ct *CPUTool) GetDescription(locale i18n.Locale) string {
+	return i18n.T(locale, "cpu.description")
 }
 
 // GetInputSchema 获取输入模式
-func (ct *CPUTool) GetInputSchema() types.InputSchema {
+func (ct *CPUTool) GetInputSchema(locale i18n.Locale) types.InputSchema {
 	return types.InputSchema{
 		Type: "object",
 		Properties: map[string]types.Property{
 			"duration": {
 				Type:        "string",
-				Description: "监控持续时间 (1s, 5s, 10s)",
-				Enum:        []string{"1s", "5s", "10s"},
+				Description: i18n.T(locale, "cpu.arg.duration"),
+				Enum:        []any{"1s", "5s", "10s"},
 				Default:     "1s",
 			},
+			render.FormatArgument: render.FormatProperty(locale),
+			i18n.Argument:         localeProperty(locale),
 		},
+		AdditionalProperties: ptr(false),
 	}
 }
 
+// GetAnnotations 获取工具注解
+func (ct *CPUTool) GetAnnotations(locale i18n.Locale) types.ToolAnnotations {
+	return readOnlyAnnotations(i18n.T(locale, "cpu.toolTitle"))
+}
+
+// GetOutputSchema 获取输出模式，与结构化结果 types.CPUInfo 对应
+func (ct *CPUTool) GetOutputSchema() types.OutputSchema {
+	return types.OutputSchemaOf(types.CPUInfo{})
+}
+
 // Execute 执行 CPU 监控
-func (ct *CPUTool) Execute(args map[string]interface{}) (string, error) {
+func (ct *CPUTool) Execute(ctx context.Context, args map[string]interface{}) (types.ToolOutput, error) {
 	// 解析参数
-	durationStr, _ := args["duration"].(string)
-	if durationStr == "" {
-		durationStr = "1s"
+	locale := i18n.FromContext(ctx)
+	var params cpuArguments
+	if err := types.DecodeArguments(ct.GetInputSchema(locale), args, &params); err != nil {
+		return types.ToolOutput{}, err
+	}
+	durationStr := params.Duration
+	format, err := render.FormatFromArgs(locale, args)
+	if err != nil {
+		return types.ToolOutput{}, err
 	}
 
 	// 获取 CPU 信息
-	cpuInfo, err := ct.getCPUInfo(durationStr)
+	cpuInfo, err := ct.getCPUInfo(ctx, durationStr)
 	if err != nil {
-		return "", fmt.Errorf("获取 CPU 信息失败: %v", err)
+		return types.ToolOutput{}, i18n.Errorf(locale, "cpu.error.info", err)
 	}
 
-	return ct.formatCPUInfo(cpuInfo, durationStr), nil
+	return renderOutput(format, cpuInfo, ct.buildReport(locale, cpuInfo, durationStr))
 }
 
 // getCPUInfo 获取 CPU 信息
-func (ct *CPUTool) getCPUInfo(durationStr string) (types.CPUInfo, error) {
+func (ct *CPUTool) getCPUInfo(ctx context.Context, durationStr string) (types.CPUInfo, error) {
 	var cpuInfo types.CPUInfo
+	locale := i18n.FromContext(ctx)
 
 	// 解析持续时间
 	duration, err := time.ParseDuration(durationStr)
@@ -69,9 +97,9 @@ func (ct *CPUTool) getCPUInfo(durationStr string) (types.CPUInfo, error) {
 	}
 
 	// 获取 CPU 基本信息
-	cpuInfos, err := cpu.Info()
+	cpuInfos, err := cpu.InfoWithContext(ctx)
 	if err != nil {
-		return cpuInfo, fmt.Errorf("获取 CPU 基本信息失败: %v", err)
+		return cpuInfo, i18n.Errorf(locale, "cpu.error.basic", err)
 	}
 
 	if len(cpuInfos) > 0 {
@@ -82,16 +110,27 @@ func (ct *CPUTool) getCPUInfo(durationStr string) (types.CPUInfo, error) {
 
 	cpuInfo.LogicalCores = runtime.NumCPU()
 
-	// 获取 CPU 使用率
-	cpuPercent, err := cpu.Percent(duration, true)
+	// 两次采样的总耗时，用于汇报进度
+	total := 2 * duration.Seconds()
+
+	// 获取 CPU 使用率（采样期间可被 ctx 取消）
+	var cpuPercent []float64
+	err = sampleWithProgress(ctx, duration, 0, total, i18n.T(locale, "cpu.progress.perCore"), func() (err error) {
+		cpuPercent, err = cpu.PercentWithContext(ctx, duration, true)
+		return err
+	})
 	if err != nil {
-		return cpuInfo, fmt.Errorf("获取 CPU 使用率失败: %v", err)
+		return cpuInfo, i18n.Errorf(locale, "cpu.error.perCore", err)
 	}
 
 	// 获取总体 CPU 使用率
-	totalCPU, err := cpu.Percent(duration, false)
+	var totalCPU []float64
+	err = sampleWithProgress(ctx, duration, duration.Seconds(), total, i18n.T(locale, "cpu.progress.total"), func() (err error) {
+		totalCPU, err = cpu.PercentWithContext(ctx, duration, false)
+		return err
+	})
 	if err != nil {
-		return cpuInfo, fmt.Errorf("获取总体 CPU 使用率失败: %v", err)
+		return cpuInfo, i18n.Errorf(locale, "cpu.error.total", err)
 	}
 
 	// 设置使用率数据
@@ -105,34 +144,42 @@ func (ct *CPUTool) getCPUInfo(durationStr string) (types.CPUInfo, error) {
 	return cpuInfo, nil
 }
 
-// formatCPUInfo 格式化 CPU 信息输出
-func (ct *CPUTool) formatCPUInfo(cpuInfo types.CPUInfo, durationStr string) string {
-	var result string
-
-	result += "🖥️  CPU 信息\n"
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-	result += fmt.Sprintf("型号: %s\n", cpuInfo.ModelName)
-	result += fmt.Sprintf("核心数: %d 物理核心, %d 逻辑核心\n", cpuInfo.Cores, cpuInfo.LogicalCores)
-	result += fmt.Sprintf("主频: %.2f GHz\n", cpuInfo.Frequency)
-
-	result += fmt.Sprintf("\n📊 CPU 使用率 (监控时长: %s)\n", durationStr)
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-	result += fmt.Sprintf("总体使用率: %.2f%%\n\n", cpuInfo.Usage.Total)
-
-	result += "各核心使用率:\n"
+// buildReport 描述 CPU 信息的输出内容
+func (ct *CPUTool) buildReport(locale i18n.Locale, cpuInfo types.CPUInfo, durationStr string) render.Report {
+	perCore := &render.Table{Columns: []string{i18n.T(locale, "cpu.core"), i18n.T(locale, "cpu.usage")}}
 	for i, percent := range cpuInfo.Usage.PerCore {
-		result += fmt.Sprintf("  核心 %d: %.2f%%\n", i+1, percent)
+		perCore.Rows = append(perCore.Rows, []string{i18n.T(locale, "cpu.coreN", i+1), fmt.Sprintf("%.2f%%", percent)})
 	}
 
-	result += fmt.Sprintf("\n📅 更新时间: %s\n", cpuInfo.LastUpdated.Format("2006-01-02 15:04:05"))
-
-	return result
+	return render.Report{
+		Locale: locale,
+		Icon:   "🖥️",
+		Title:  i18n.T(locale, "cpu.title"),
+		Sections: []render.Section{
+			{
+				Fields: []render.Field{
+					{Label: i18n.T(locale, "cpu.model"), Value: cpuInfo.ModelName},
+					{Label: i18n.T(locale, "cpu.cores"), Value: i18n.T(locale, "cpu.coresValue", cpuInfo.Cores, cpuInfo.LogicalCores)},
+					{Label: i18n.T(locale, "cpu.frequency"), Value: fmt.Sprintf("%.2f GHz", cpuInfo.Frequency)},
+				},
+			},
+			{
+				Icon:  "📊",
+				Title: i18n.T(locale, "cpu.usageTitle", durationStr),
+				Fields: []render.Field{
+					{Label: i18n.T(locale, "cpu.totalUsage"), Value: fmt.Sprintf("%.2f%%", cpuInfo.Usage.Total)},
+				},
+				Table: perCore,
+			},
+		},
+		Updated: cpuInfo.LastUpdated,
+	}
 }
 
 // GetCPUData 获取 CPU 数据（供其他组件使用）
-func (ct *CPUTool) GetCPUData(duration time.Duration) (types.CPUInfo, error) {
+func (ct *CPUTool) GetCPUData(ctx context.Context, duration time.Duration) (types.CPUInfo, error) {
 	durationStr := duration.String()
-	return ct.getCPUInfo(durationStr)
+	return ct.getCPUInfo(ctx, durationStr)
 }
 
 // CPUTool returns the tool definition and handler for the cpu_status tool.
@@ -1,7 +1,10 @@
 package tools
 
 import (
+	"context"
 	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
 	"go-mcp/mcp/types"
 	"time"
 
@@ -12,6 +15,11 @@ import (
 type DiskTool struct {
 }
 
+// diskArguments 为 disk_info 的参数
+type diskArguments struct {
+	ShowAll bool `json:"show_all"`
+}
+
 // NewDiskTool 创建新的磁盘监控工具
 func NewDiskTool() *DiskTool {
 	return &DiskTool{}
@@ -23,55 +31,81 @@ func (dt *DiskTool) GetName() string {
 }
 
 // GetDescription 获取工具描述
-func (dt *DiskTool) GetDescription() string {
-	return "获取磁盘使用情况"
+func (dt *DiskTool) GetDescription(locale i18n.Locale) string {
+	return i18n.T(locale, "disk.description")
 }
 
 // GetInputSchema 获取输入模式
-func (dt *DiskTool) GetInputSchema() types.InputSchema {
+func (dt *DiskTool) GetInputSchema(locale i18n.Locale) types.InputSchema {
 	return types.InputSchema{
 		Type: "object",
 		Properties: map[string]types.Property{
 			"show_all": {
-				Type:        "string",
-				Description: "是否显示所有分区（包括系统分区）",
-				Enum:        []string{"true", "false"},
-				Default:     "false",
+				Type:        "boolean",
+				Description: i18n.T(locale, "disk.arg.showAll"),
+				Default:     false,
 			},
+			render.FormatArgument: render.FormatProperty(locale),
+			i18n.Argument:         localeProperty(locale),
 		},
+		AdditionalProperties: ptr(false),
 	}
 }
 
+// GetAnnotations 获取工具注解
+func (dt *DiskTool) GetAnnotations(locale i18n.Locale) types.ToolAnnotations {
+	return readOnlyAnnotations(i18n.T(locale, "disk.toolTitle"))
+}
+
+// GetOutputSchema 获取输出模式，与结构化结果 types.DiskInfo 对应
+func (dt *DiskTool) GetOutputSchema() types.OutputSchema {
+	return types.OutputSchemaOf(types.DiskInfo{})
+}
+
 // Execute 执行磁盘监控
-func (dt *DiskTool) Execute(args map[string]interface{}) (string, error) {
+func (dt *DiskTool) Execute(ctx context.Context, args map[string]interface{}) (types.ToolOutput, error) {
 	// 解析参数
-	showAllStr, _ := args["show_all"].(string)
-	showAll := showAllStr == "true"
+	locale := i18n.FromContext(ctx)
+	var params diskArguments
+	if err := types.DecodeArguments(dt.GetInputSchema(locale), args, &params); err != nil {
+		return types.ToolOutput{}, err
+	}
+	showAll := params.ShowAll
+	format, err := render.FormatFromArgs(locale, args)
+	if err != nil {
+		return types.ToolOutput{}, err
+	}
 
 	// 获取磁盘信息
-	diskInfo, err := dt.getDiskInfo(showAll)
+	diskInfo, err := dt.getDiskInfo(ctx, showAll)
 	if err != nil {
-		return "", fmt.Errorf("获取磁盘信息失败: %v", err)
+		return types.ToolOutput{}, i18n.Errorf(locale, "disk.error.info", err)
 	}
 
-	return dt.formatDiskInfo(diskInfo), nil
+	return renderOutput(format, diskInfo, dt.buildReport(locale, diskInfo))
 }
 
 // getDiskInfo 获取磁盘信息
-func (dt *DiskTool) getDiskInfo(showAll bool) (types.DiskInfo, error) {
-	var diskInfo types.DiskInfo
+func (dt *DiskTool) getDiskInfo(ctx context.Context, showAll bool) (types.DiskInfo, error) {
+	// 切片初始化为空而不是 nil，保证结构化输出中为 [] 而不是 null
+	diskInfo := types.DiskInfo{Partitions: []types.DiskPartition{}}
 
 	// 获取磁盘分区
-	partitions, err := disk.Partitions(showAll)
+	partitions, err := disk.PartitionsWithContext(ctx, showAll)
 	if err != nil {
-		return diskInfo, fmt.Errorf("获取磁盘分区失败: %v", err)
+		return diskInfo, i18n.Errorf(i18n.FromContext(ctx), "disk.error.partitions", err)
 	}
 
 	for _, partition := range partitions {
+		if err := ctx.Err(); err != nil {
+			return diskInfo, err
+		}
+
 		// 获取分区使用情况
-		usage, err := disk.Usage(partition.Mountpoint)
+		usage, err := disk.UsageWithContext(ctx, partition.Mountpoint)
 		if err != nil {
 			// 跳过无法访问的分区
+			types.LoggerFrom(ctx).DebugContext(ctx, "跳过无法访问的分区", "mountpoint", partition.Mountpoint, "error", err)
 			continue
 		}
 
@@ -128,36 +162,32 @@ func (dt *DiskTool) shouldSkipPartition(mountpoint, fstype string) bool {
 	return false
 }
 
-// formatDiskInfo 格式化磁盘信息输出
-func (dt *DiskTool) formatDiskInfo(diskInfo types.DiskInfo) string {
-	var result string
-
-	result += "💽 磁盘信息\n"
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+// buildReport 描述磁盘信息的输出内容
+func (dt *DiskTool) buildReport(locale i18n.Locale, diskInfo types.DiskInfo) render.Report {
+	section := render.Section{}
 
 	if len(diskInfo.Partitions) == 0 {
-		result += "未找到可用的磁盘分区\n"
+		section.Note = i18n.T(locale, "disk.empty")
 	} else {
-		result += fmt.Sprintf("%-20s %-10s %-12s %-12s %-12s %-10s\n",
-			"挂载点", "文件系统", "总大小", "已使用", "可用", "使用率")
-		result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+		table := &render.Table{Columns: []string{
+			i18n.T(locale, "disk.mountpoint"),
+			i18n.T(locale, "disk.fstype"),
+			i18n.T(locale, "disk.size"),
+			i18n.T(locale, "disk.used"),
+			i18n.T(locale, "disk.free"),
+			i18n.T(locale, "disk.usedPercent"),
+		}}
 
 		var totalSize, totalUsed, totalFree uint64
 		for _, partition := range diskInfo.Partitions {
-			// 截断过长的挂载点
-			mountpoint := partition.Mountpoint
-			if len(mountpoint) > 20 {
-				mountpoint = mountpoint[:17] + "..."
-			}
-
-			result += fmt.Sprintf("%-20s %-10s %-12s %-12s %-12s %-10.1f%%\n",
-				mountpoint,
+			table.Rows = append(table.Rows, []string{
+				partition.Mountpoint,
 				partition.Fstype,
 				formatBytes(partition.Total),
 				formatBytes(partition.Used),
 				formatBytes(partition.Free),
-				partition.UsedPercent,
-			)
+				fmt.Sprintf("%.1f%%", partition.UsedPercent),
+			})
 
 			// 累计总计
 			totalSize += partition.Total
@@ -166,35 +196,39 @@ func (dt *DiskTool) formatDiskInfo(diskInfo types.DiskInfo) string {
 		}
 
 		// 显示总计
-		if len(diskInfo.Partitions) > 1 {
-			result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+		if len(diskInfo.Partitions) > 1 && totalSize > 0 {
 			totalUsedPercent := float64(totalUsed) / float64(totalSize) * 100
-			result += fmt.Sprintf("%-20s %-10s %-12s %-12s %-12s %-10.1f%%\n",
-				"总计",
+			table.Footer = []string{
+				i18n.T(locale, "disk.total"),
 				"-",
 				formatBytes(totalSize),
 				formatBytes(totalUsed),
 				formatBytes(totalFree),
-				totalUsedPercent,
-			)
+				fmt.Sprintf("%.1f%%", totalUsedPercent),
+			}
 		}
+		section.Table = table
 	}
 
-	result += fmt.Sprintf("\n📅 更新时间: %s\n", diskInfo.LastUpdated.Format("2006-01-02 15:04:05"))
-
-	return result
+	return render.Report{
+		Locale:   locale,
+		Icon:     "💽",
+		Title:    i18n.T(locale, "disk.title"),
+		Sections: []render.Section{section},
+		Updated:  diskInfo.LastUpdated,
+	}
 }
 
 // GetDiskData 获取磁盘数据（供其他组件使用）
-func (dt *DiskTool) GetDiskData(showAll bool) (types.DiskInfo, error) {
-	return dt.getDiskInfo(showAll)
+func (dt *DiskTool) GetDiskData(ctx context.Context, showAll bool) (types.DiskInfo, error) {
+	return dt.getDiskInfo(ctx, showAll)
 }
 
 // GetDiskUsageByPath 获取指定路径的磁盘使用情况
-func (dt *DiskTool) GetDiskUsageByPath(path string) (types.DiskPartition, error) {
+func (dt *DiskTool) GetDiskUsageByPath(ctx context.Context, path string) (types.DiskPartition, error) {
 	var partition types.DiskPartition
 
-	usage, err := disk.Usage(path)
+	usage, err := disk.UsageWithContext(ctx, path)
 	if err != nil {
 		return partition, fmt.Errorf("获取路径 %s 的磁盘使用情况失败: %v", path, err)
 	}
@@ -213,8 +247,8 @@ func (dt *DiskTool) GetDiskUsageByPath(path string) (types.DiskPartition, error)
 }
 
 // GetDiskIOStats 获取磁盘 I/O 统计信息
-func (dt *DiskTool) GetDiskIOStats() (map[string]interface{}, error) {
-	ioStats, err := disk.IOCounters()
+func (dt *DiskTool) GetDiskIOStats(ctx context.Context) (map[string]interface{}, error) {
+	ioStats, err := disk.IOCountersWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("获取磁盘 I/O 统计失败: %v", err)
 	}
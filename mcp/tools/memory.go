@@ -1,7 +1,10 @@
 package tools
 
 import (
+	"context"
 	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
 	"go-mcp/mcp/types"
 	"time"
 
@@ -23,44 +26,68 @@ func (mt *MemoryTool) GetName() string {
 }
 
 // GetDescription 获取工具描述
-func (mt *MemoryTool) GetDescription() string {
-	return "获取内存使用情况详细信息"
+func (mt *MemoryTool) GetDescription(locale i18n.Locale) string {
+	return i18n.T(locale, "memory.description")
 }
 
 // GetInputSchema 获取输入模式
-func (mt *MemoryTool) GetInputSchema() types.InputSchema {
+func (mt *MemoryTool) GetInputSchema(locale i18n.Locale) types.InputSchema {
 	return types.InputSchema{
-		Type:       "object",
-		Properties: map[string]types.Property{},
+		Type: "object",
+		Properties: map[string]types.Property{
+			render.FormatArgument: render.FormatProperty(locale),
+			i18n.Argument:         localeProperty(locale),
+		},
+		AdditionalProperties: ptr(false),
 	}
 }
 
+// GetAnnotations 获取工具注解
+func (mt *MemoryTool) GetAnnotations(locale i18n.Locale) types.ToolAnnotations {
+	return readOnlyAnnotations(i18n.T(locale, "memory.toolTitle"))
+}
+
+// GetOutputSchema 获取输出模式，与结构化结果 types.MemoryInfo 对应
+func (mt *MemoryTool) GetOutputSchema() types.OutputSchema {
+	return types.OutputSchemaOf(types.MemoryInfo{})
+}
+
 // Execute 执行内存监控
-func (mt *MemoryTool) Execute(map[string]interface{}) (string, error) {
+func (mt *MemoryTool) Execute(ctx context.Context, args map[string]interface{}) (types.ToolOutput, error) {
+	// 解析参数
+	locale := i18n.FromContext(ctx)
+	if err := mt.GetInputSchema(locale).Validate(args); err != nil {
+		return types.ToolOutput{}, err
+	}
+	format, err := render.FormatFromArgs(locale, args)
+	if err != nil {
+		return types.ToolOutput{}, err
+	}
 
 	// 获取内存信息
-	memInfo, err := mt.getMemoryInfo()
+	memInfo, err := mt.getMemoryInfo(ctx)
 	if err != nil {
-		return "", fmt.Errorf("获取内存信息失败: %v", err)
+		return types.ToolOutput{}, i18n.Errorf(locale, "memory.error.info", err)
 	}
 
-	return mt.formatMemoryInfo(memInfo), nil
+	return renderOutput(format, memInfo, mt.buildReport(locale, memInfo))
 }
 
 // getMemoryInfo 获取内存信息
-func (mt *MemoryTool) getMemoryInfo() (types.MemoryInfo, error) {
+func (mt *MemoryTool) getMemoryInfo(ctx context.Context) (types.MemoryInfo, error) {
 	var memInfo types.MemoryInfo
+	locale := i18n.FromContext(ctx)
 
 	// 获取虚拟内存信息
-	vmStat, err := mem.VirtualMemory()
+	vmStat, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
-		return memInfo, fmt.Errorf("获取虚拟内存信息失败: %v", err)
+		return memInfo, i18n.Errorf(locale, "memory.error.virtual", err)
 	}
 
 	// 获取交换内存信息
-	swapStat, err := mem.SwapMemory()
+	swapStat, err := mem.SwapMemoryWithContext(ctx)
 	if err != nil {
-		return memInfo, fmt.Errorf("获取交换内存信息失败: %v", err)
+		return memInfo, i18n.Errorf(locale, "memory.error.swap", err)
 	}
 
 	// 填充内存信息
@@ -83,33 +110,40 @@ func (mt *MemoryTool) getMemoryInfo() (types.MemoryInfo, error) {
 	return memInfo, nil
 }
 
-// formatMemoryInfo 格式化内存信息输出
-func (mt *MemoryTool) formatMemoryInfo(memInfo types.MemoryInfo) string {
-	var result string
-
-	result += "💾 内存信息\n"
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-	result += fmt.Sprintf("总内存: %s\n", formatBytes(memInfo.Total))
-	result += fmt.Sprintf("已使用: %s (%.2f%%)\n", formatBytes(memInfo.Used), memInfo.UsedPercent)
-	result += fmt.Sprintf("可用内存: %s\n", formatBytes(memInfo.Available))
-	result += fmt.Sprintf("空闲内存: %s\n", formatBytes(memInfo.Free))
-	result += fmt.Sprintf("缓冲区: %s\n", formatBytes(memInfo.Buffers))
-	result += fmt.Sprintf("缓存: %s\n", formatBytes(memInfo.Cached))
-
-	result += "\n🔄 交换内存\n"
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-	result += fmt.Sprintf("总交换: %s\n", formatBytes(memInfo.Swap.Total))
-	result += fmt.Sprintf("已使用: %s (%.2f%%)\n", formatBytes(memInfo.Swap.Used), memInfo.Swap.UsedPercent)
-	result += fmt.Sprintf("空闲交换: %s\n", formatBytes(memInfo.Swap.Free))
-
-	result += fmt.Sprintf("\n📅 更新时间: %s\n", memInfo.LastUpdated.Format("2006-01-02 15:04:05"))
-
-	return result
+// buildReport 描述内存信息的输出内容
+func (mt *MemoryTool) buildReport(locale i18n.Locale, memInfo types.MemoryInfo) render.Report {
+	return render.Report{
+		Locale: locale,
+		Icon:   "💾",
+		Title:  i18n.T(locale, "memory.title"),
+		Sections: []render.Section{
+			{
+				Fields: []render.Field{
+					{Label: i18n.T(locale, "memory.total"), Value: formatBytes(memInfo.Total)},
+					{Label: i18n.T(locale, "memory.used"), Value: fmt.Sprintf("%s (%.2f%%)", formatBytes(memInfo.Used), memInfo.UsedPercent)},
+					{Label: i18n.T(locale, "memory.available"), Value: formatBytes(memInfo.Available)},
+					{Label: i18n.T(locale, "memory.free"), Value: formatBytes(memInfo.Free)},
+					{Label: i18n.T(locale, "memory.buffers"), Value: formatBytes(memInfo.Buffers)},
+					{Label: i18n.T(locale, "memory.cached"), Value: formatBytes(memInfo.Cached)},
+				},
+			},
+			{
+				Icon:  "🔄",
+				Title: i18n.T(locale, "memory.swapTitle"),
+				Fields: []render.Field{
+					{Label: i18n.T(locale, "memory.swapTotal"), Value: formatBytes(memInfo.Swap.Total)},
+					{Label: i18n.T(locale, "memory.used"), Value: fmt.Sprintf("%s (%.2f%%)", formatBytes(memInfo.Swap.Used), memInfo.Swap.UsedPercent)},
+					{Label: i18n.T(locale, "memory.swapFree"), Value: formatBytes(memInfo.Swap.Free)},
+				},
+			},
+		},
+		Updated: memInfo.LastUpdated,
+	}
 }
 
 // GetMemoryData 获取内存数据（供其他组件使用）
-func (mt *MemoryTool) GetMemoryData() (types.MemoryInfo, error) {
-	return mt.getMemoryInfo()
+func (mt *MemoryTool) GetMemoryData(ctx context.Context) (types.MemoryInfo, error) {
+	return mt.getMemoryInfo(ctx)
 }
 
 // formatBytes 格式化字节数
@@ -1,8 +1,12 @@
 package tools
 
 import (
+	"context"
 	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
 	"go-mcp/mcp/types"
+	"sort"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/net"
@@ -12,6 +16,12 @@ import (
 type NetworkTool struct {
 }
 
+// networkArguments 为 network_stats 的参数
+type networkArguments struct {
+	ShowConnections bool   `json:"show_connections"`
+	InterfaceFilter string `json:"interface_filter"`
+}
+
 // NewNetworkTool 创建新的网络监控工具
 func NewNetworkTool() *NetworkTool {
 	return &NetworkTool{}
@@ -23,55 +33,82 @@ func (nt *NetworkTool) GetName() string {
 }
 
 // GetDescription 获取工具描述
-func (nt *NetworkTool) GetDescription() string {
-	return "获取网络连接状态和传输速度"
+func (nt *NetworkTool) GetDescription(locale i18n.Locale) string {
+	return i18n.T(locale, "network.description")
 }
 
 // GetInputSchema 获取输入模式
-func (nt *NetworkTool) GetInputSchema() types.InputSchema {
+func (nt *NetworkTool) GetInputSchema(locale i18n.Locale) types.InputSchema {
 	return types.InputSchema{
 		Type: "object",
 		Properties: map[string]types.Property{
 			"show_connections": {
-				Type:        "string",
-				Description: "是否显示连接详情",
-				Enum:        []string{"true", "false"},
-				Default:     "false",
+				Type:        "boolean",
+				Description: i18n.T(locale, "network.arg.showConnections"),
+				Default:     false,
 			},
 			"interface_filter": {
 				Type:        "string",
-				Description: "网络接口过滤器（为空则显示所有）",
+				Description: i18n.T(locale, "network.arg.interfaceFilter"),
 				Default:     "",
 			},
+			render.FormatArgument: render.FormatProperty(locale),
+			i18n.Argument:         localeProperty(locale),
 		},
+		AdditionalProperties: ptr(false),
 	}
 }
 
+// GetAnnotations 获取工具注解
+func (nt *NetworkTool) GetAnnotations(locale i18n.Locale) types.ToolAnnotations {
+	return readOnlyAnnotations(i18n.T(locale, "network.toolTitle"))
+}
+
+// GetOutputSchema 获取输出模式，与结构化结果 types.NetworkInfo 对应
+func (nt *NetworkTool) GetOutputSchema() types.OutputSchema {
+	return types.OutputSchemaOf(types.NetworkInfo{})
+}
+
 // Execute 执行网络监控
-func (nt *NetworkTool) Execute(args map[string]interface{}) (string, error) {
+func (nt *NetworkTool) Execute(ctx context.Context, args map[string]interface{}) (types.ToolOutput, error) {
 	// 解析参数
-	showConnStr, _ := args["show_connections"].(string)
-	showConnections := showConnStr == "true"
+	locale := i18n.FromContext(ctx)
+	var params networkArguments
+	if err := types.DecodeArguments(nt.GetInputSchema(locale), args, &params); err != nil {
+		return types.ToolOutput{}, err
+	}
+	showConnections := params.ShowConnections
+	interfaceFilter := params.InterfaceFilter
 
-	interfaceFilter, _ := args["interface_filter"].(string)
+	format, err := render.FormatFromArgs(locale, args)
+	if err != nil {
+		return types.ToolOutput{}, err
+	}
 
 	// 获取网络信息
-	netInfo, err := nt.getNetworkInfo(showConnections, interfaceFilter)
+	netInfo, err := nt.getNetworkInfo(ctx, showConnections, interfaceFilter)
 	if err != nil {
-		return "", fmt.Errorf("获取网络信息失败: %v", err)
+		return types.ToolOutput{}, i18n.Errorf(locale, "network.error.info", err)
 	}
 
-	return nt.formatNetworkInfo(netInfo, showConnections), nil
+	return renderOutput(format, netInfo, nt.buildReport(locale, netInfo, showConnections))
 }
 
 // getNetworkInfo 获取网络信息
-func (nt *NetworkTool) getNetworkInfo(showConnections bool, interfaceFilter string) (types.NetworkInfo, error) {
-	var netInfo types.NetworkInfo
+func (nt *NetworkTool) getNetworkInfo(ctx context.Context, showConnections bool, interfaceFilter string) (types.NetworkInfo, error) {
+	// 切片与 map 初始化为空而不是 nil，保证结构化输出中为 []/{} 而不是 null
+	netInfo := types.NetworkInfo{
+		Interfaces: []types.NetworkInterface{},
+		Connections: types.NetworkConnections{
+			ByStatus:   map[string]int{},
+			ByProtocol: map[string]int{},
+		},
+	}
 
 	// 获取网络接口统计
-	netStats, err := net.IOCounters(true)
+	netStats, err := net.IOCountersWithContext(ctx, true)
 	if err != nil {
-		return netInfo, fmt.Errorf("获取网络接口统计失败: %v", err)
+		return netInfo, i18n.Errorf(i18n.FromContext(ctx), "network.error.interfaces", err)
 	}
 
 	// 过滤网络接口
@@ -108,7 +145,7 @@ func (nt *NetworkTool) getNetworkInfo(showConnections bool, interfaceFilter stri
 
 	// 获取网络连接信息
 	if showConnections {
-		connections, err := net.Connections("all")
+		connections, err := net.ConnectionsWithContext(ctx, "all")
 		if err == nil {
 			netInfo.Connections = nt.processConnections(connections)
 		}
@@ -153,87 +190,108 @@ func (nt *NetworkTool) processConnections(connections []net.ConnectionStat) type
 	return netConn
 }
 
-// formatNetworkInfo 格式化网络信息输出
-func (nt *NetworkTool) formatNetworkInfo(netInfo types.NetworkInfo, showConnections bool) string {
-	var result string
-
-	result += "🌐 网络状态\n"
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+// buildReport 描述网络信息的输出内容
+func (nt *NetworkTool) buildReport(locale i18n.Locale, netInfo types.NetworkInfo, showConnections bool) render.Report {
+	var sections []render.Section
 
 	// 网络接口统计
 	if len(netInfo.Interfaces) > 0 {
-		result += "网络接口统计:\n"
-		result += fmt.Sprintf("%-15s %-12s %-12s %-12s %-12s %-8s %-8s\n",
-			"接口", "发送(MB)", "接收(MB)", "发送包数", "接收包数", "发送错误", "接收错误")
-		result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-
+		table := &render.Table{Columns: []string{
+			i18n.T(locale, "network.interface"),
+			i18n.T(locale, "network.sentMB"),
+			i18n.T(locale, "network.recvMB"),
+			i18n.T(locale, "network.packetsSent"),
+			i18n.T(locale, "network.packetsRecv"),
+			i18n.T(locale, "network.errorsOut"),
+			i18n.T(locale, "network.errorsIn"),
+		}}
 		for _, iface := range netInfo.Interfaces {
-			result += fmt.Sprintf("%-15s %-12.2f %-12.2f %-12d %-12d %-8d %-8d\n",
+			table.Rows = append(table.Rows, []string{
 				iface.Name,
-				float64(iface.BytesSent)/(1024*1024),
-				float64(iface.BytesRecv)/(1024*1024),
-				iface.PacketsSent,
-				iface.PacketsRecv,
-				iface.ErrorsOut,
-				iface.ErrorsIn,
-			)
+				fmt.Sprintf("%.2f", float64(iface.BytesSent)/(1024*1024)),
+				fmt.Sprintf("%.2f", float64(iface.BytesRecv)/(1024*1024)),
+				fmt.Sprintf("%d", iface.PacketsSent),
+				fmt.Sprintf("%d", iface.PacketsRecv),
+				fmt.Sprintf("%d", iface.ErrorsOut),
+				fmt.Sprintf("%d", iface.ErrorsIn),
+			})
 		}
+		sections = append(sections, render.Section{Title: i18n.T(locale, "network.interfacesTitle"), Table: table})
 	}
 
 	// 网络连接统计
 	if showConnections && netInfo.Connections.Total > 0 {
-		result += "\n🔗 网络连接统计:\n"
-		result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-		result += fmt.Sprintf("总连接数: %d\n", netInfo.Connections.Total)
+		sections = append(sections, render.Section{
+			Icon:   "🔗",
+			Title:  i18n.T(locale, "network.connectionsTitle"),
+			Fields: []render.Field{{Label: i18n.T(locale, "network.totalConnections"), Value: fmt.Sprintf("%d", netInfo.Connections.Total)}},
+		})
 
 		if len(netInfo.Connections.ByStatus) > 0 {
-			result += "\n按状态分类:\n"
-			for status, count := range netInfo.Connections.ByStatus {
-				result += fmt.Sprintf("  %s: %d\n", status, count)
-			}
+			sections = append(sections, render.Section{Title: i18n.T(locale, "network.byStatus"), Fields: countFields(netInfo.Connections.ByStatus)})
 		}
-
 		if len(netInfo.Connections.ByProtocol) > 0 {
-			result += "\n按协议分类:\n"
-			for protocol, count := range netInfo.Connections.ByProtocol {
-				result += fmt.Sprintf("  %s: %d\n", protocol, count)
-			}
+			sections = append(sections, render.Section{Title: i18n.T(locale, "network.byProtocol"), Fields: countFields(netInfo.Connections.ByProtocol)})
 		}
 
 		// 显示部分连接详情
 		if len(netInfo.Connections.Details) > 0 {
-			result += "\n连接详情 (前20个):\n"
-			result += fmt.Sprintf("%-10s %-15s %-6s %-15s %-6s %-12s\n",
-				"协议", "本地IP", "端口", "远程IP", "端口", "状态")
-			result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-
+			port := i18n.T(locale, "network.port")
+			table := &render.Table{Columns: []string{
+				i18n.T(locale, "network.protocol"),
+				i18n.T(locale, "network.localIP"),
+				port,
+				i18n.T(locale, "network.remoteIP"),
+				port,
+				i18n.T(locale, "network.status"),
+			}}
 			for _, detail := range netInfo.Connections.Details {
-				result += fmt.Sprintf("%-10s %-15s %-6d %-15s %-6d %-12s\n",
+				table.Rows = append(table.Rows, []string{
 					detail.Protocol,
 					detail.LocalIP,
-					detail.LocalPort,
+					fmt.Sprintf("%d", detail.LocalPort),
 					detail.RemoteIP,
-					detail.RemotePort,
+					fmt.Sprintf("%d", detail.RemotePort),
 					detail.Status,
-				)
+				})
 			}
+			sections = append(sections, render.Section{Title: i18n.T(locale, "network.detailsTitle"), Table: table})
 		}
 	}
 
-	result += fmt.Sprintf("\n📅 更新时间: %s\n", netInfo.LastUpdated.Format("2006-01-02 15:04:05"))
+	return render.Report{
+		Locale:   locale,
+		Icon:     "🌐",
+		Title:    i18n.T(locale, "network.title"),
+		Sections: sections,
+		Updated:  netInfo.LastUpdated,
+	}
+}
+
+// countFields 将计数表按名称排序后转换为字段列表，保证输出顺序稳定
+func countFields(counts map[string]int) []render.Field {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	return result
+	fields := make([]render.Field, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, render.Field{Label: name, Value: fmt.Sprintf("%d", counts[name])})
+	}
+	return fields
 }
 
 // GetNetworkData 获取网络数据（供其他组件使用）
-func (nt *NetworkTool) GetNetworkData(showConnections bool, interfaceFilter string) (types.NetworkInfo, error) {
-	return nt.getNetworkInfo(showConnections, interfaceFilter)
+func (nt *NetworkTool) GetNetworkData(ctx context.Context, showConnections bool, interfaceFilter string) (types.NetworkInfo, error) {
+	return nt.getNetworkInfo(ctx, showConnections, interfaceFilter)
 }
 
 // GetNetworkSpeed 计算网络传输速度（需要两次采样）
-func (nt *NetworkTool) GetNetworkSpeed(interfaceName string, interval time.Duration) (float64, float64, error) {
+func (nt *NetworkTool) GetNetworkSpeed(ctx context.Context, interfaceName string, interval time.Duration) (float64, float64, error) {
 	// 第一次采样
-	stats1, err := net.IOCounters(true)
+	stats1, err := net.IOCountersWithContext(ctx, true)
 	if err != nil {
 		return 0, 0, fmt.Errorf("获取第一次网络统计失败: %v", err)
 	}
@@ -250,11 +308,17 @@ func (nt *NetworkTool) GetNetworkSpeed(interfaceName string, interval time.Durat
 		return 0, 0, fmt.Errorf("找不到网络接口: %s", interfaceName)
 	}
 
-	// 等待间隔
-	time.Sleep(interval)
+	// 等待间隔，期间可被 ctx 取消
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
+	case <-timer.C:
+	}
 
 	// 第二次采样
-	stats2, err := net.IOCounters(true)
+	stats2, err := net.IOCountersWithContext(ctx, true)
 	if err != nil {
 		return 0, 0, fmt.Errorf("获取第二次网络统计失败: %v", err)
 	}
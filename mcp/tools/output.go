@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
+	"go-mcp/mcp/types"
+)
+
+// renderOutput 按请求的格式渲染报告，并与结构化数据一起作为工具结果返回
+func renderOutput(format render.Format, data any, report render.Report) (types.ToolOutput, error) {
+	text, err := render.Render(format, report, data)
+	if err != nil {
+		return types.ToolOutput{}, err
+	}
+	return types.ToolOutput{Data: data, Text: text}, nil
+}
+
+// localeProperty 返回所有工具共用的 locale 参数定义。不声明枚举值，
+// 以便接受 en-US、zh-Hans 等可以规范化为支持语言的标签
+func localeProperty(locale i18n.Locale) types.Property {
+	return types.Property{
+		Type:        "string",
+		Description: i18n.T(locale, "arg.locale"),
+	}
+}
+
+// readOnlyAnnotations 返回只读监控工具的注解：不修改系统、只读取本机信息，客户端可以免确认调用
+func readOnlyAnnotations(title string) types.ToolAnnotations {
+	return types.ToolAnnotations{
+		Title:         title,
+		ReadOnlyHint:  ptr(true),
+		OpenWorldHint: ptr(false),
+	}
+}
+
+// ptr 返回指向 v 的指针，用于填写模式中的可选约束
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -1,19 +1,30 @@
 package tools
 
 import (
+	"context"
 	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
 	"go-mcp/mcp/types"
 	"sort"
-	"strconv"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
 )
 
+// progressBatch 扫描进程时每隔多少个进程汇报一次进度
+const progressBatch = 50
+
 // ProcessTool 进程监控工具
 type ProcessTool struct {
 }
 
+// processArguments 为 top_processes 的参数
+type processArguments struct {
+	SortBy string `json:"sort_by"`
+	Limit  int    `json:"limit"`
+}
+
 // NewProcessTool 创建新的进程监控工具
 func NewProcessTool() *ProcessTool {
 	return &ProcessTool{}
@@ -25,82 +36,109 @@ func (pt *ProcessTool) GetName() string {
 }
 
 // GetDescription 获取工具描述
-func (pt *ProcessTool) GetDescription() string {
-	return "获取 CPU 或内存占用最高的进程"
+func (pt *ProcessTool) GetDescription(locale i18n.Locale) string {
+	return i18n.T(locale, "process.description")
 }
 
 // GetInputSchema 获取输入模式
-func (pt *ProcessTool) GetInputSchema() types.InputSchema {
+func (pt *ProcessTool) GetInputSchema(locale i18n.Locale) types.InputSchema {
 	return types.InputSchema{
 		Type: "object",
 		Properties: map[string]types.Property{
 			"sort_by": {
 				Type:        "string",
-				Description: "排序方式: cpu 或 memory",
-				Enum:        []string{"cpu", "memory"},
+				Description: i18n.T(locale, "process.arg.sortBy"),
+				Enum:        []any{"cpu", "memory"},
 				Default:     "memory",
 			},
 			"limit": {
-				Type:        "string",
-				Description: "返回进程数量",
-				Default:     "10",
+				Type:        "integer",
+				Description: i18n.T(locale, "process.arg.limit"),
+				Minimum:     ptr(1.0),
+				Maximum:     ptr(100.0),
+				Default:     10,
 			},
+			render.FormatArgument: render.FormatProperty(locale),
+			i18n.Argument:         localeProperty(locale),
 		},
+		AdditionalProperties: ptr(false),
 	}
 }
 
+// GetAnnotations 获取工具注解
+func (pt *ProcessTool) GetAnnotations(locale i18n.Locale) types.ToolAnnotations {
+	return readOnlyAnnotations(i18n.T(locale, "process.toolTitle"))
+}
+
+// GetOutputSchema 获取输出模式，与结构化结果 types.ProcessList 对应
+func (pt *ProcessTool) GetOutputSchema() types.OutputSchema {
+	return types.OutputSchemaOf(types.ProcessList{})
+}
+
 // Execute 执行进程监控
-func (pt *ProcessTool) Execute(args map[string]interface{}) (string, error) {
+func (pt *ProcessTool) Execute(ctx context.Context, args map[string]interface{}) (types.ToolOutput, error) {
 	// 解析参数
-	sortBy, _ := args["sort_by"].(string)
-	if sortBy == "" {
-		sortBy = "memory"
+	locale := i18n.FromContext(ctx)
+	var params processArguments
+	if err := types.DecodeArguments(pt.GetInputSchema(locale), args, &params); err != nil {
+		return types.ToolOutput{}, err
 	}
+	sortBy := params.SortBy
+	limit := params.Limit
 
-	limitStr, _ := args["limit"].(string)
-	if limitStr == "" {
-		limitStr = "10"
-	}
-	limit, _ := strconv.Atoi(limitStr)
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	format, err := render.FormatFromArgs(locale, args)
+	if err != nil {
+		return types.ToolOutput{}, err
 	}
 
 	// 获取进程信息
-	processList, err := pt.getTopProcesses(sortBy, limit)
+	processList, err := pt.getTopProcesses(ctx, sortBy, limit)
 	if err != nil {
-		return "", fmt.Errorf("获取进程信息失败: %v", err)
+		return types.ToolOutput{}, i18n.Errorf(locale, "process.error.info", err)
 	}
 
-	return pt.formatProcessList(processList, sortBy, limit), nil
+	return renderOutput(format, processList, pt.buildReport(locale, processList, sortBy, limit))
 }
 
 // getTopProcesses 获取进程信息
-func (pt *ProcessTool) getTopProcesses(sortBy string, limit int) (types.ProcessList, error) {
+func (pt *ProcessTool) getTopProcesses(ctx context.Context, sortBy string, limit int) (types.ProcessList, error) {
 	var processList types.ProcessList
+	locale := i18n.FromContext(ctx)
 
 	// 获取所有进程
-	processes, err := process.Processes()
+	processes, err := process.ProcessesWithContext(ctx)
 	if err != nil {
-		return processList, fmt.Errorf("获取进程列表失败: %v", err)
+		return processList, i18n.Errorf(locale, "process.error.list", err)
 	}
 
-	var procInfos []types.ProcessInfo
-	for _, p := range processes {
-		name, err := p.Name()
+	reporter := types.ProgressReporterFrom(ctx)
+	scanned := float64(len(processes))
+	message := i18n.T(locale, "process.progress")
+
+	procInfos := make([]types.ProcessInfo, 0, len(processes))
+	for i, p := range processes {
+		// 全量扫描耗时较长，每个进程之前检查是否已取消
+		if err := ctx.Err(); err != nil {
+			return processList, err
+		}
+		if i%progressBatch == 0 {
+			reporter.Report(float64(i), scanned, message)
+		}
+
+		name, err := p.NameWithContext(ctx)
 		if err != nil || name == "" {
 			continue
 		}
 
 		// 获取进程信息
-		memInfo, _ := p.MemoryInfo()
-		cpuPercent, _ := p.CPUPercent()
-		statusSlice, _ := p.Status()
+		memInfo, _ := p.MemoryInfoWithContext(ctx)
+		cpuPercent, _ := p.CPUPercentWithContext(ctx)
+		statusSlice, _ := p.StatusWithContext(ctx)
 		status := ""
 		if len(statusSlice) > 0 {
 			status = statusSlice[0]
 		}
-		createTime, _ := p.CreateTime()
+		createTime, _ := p.CreateTimeWithContext(ctx)
 
 		var memBytes uint64
 		var memMB float64
@@ -123,6 +161,8 @@ func (pt *ProcessTool) getTopProcesses(sortBy string, limit int) (types.ProcessL
 		procInfos = append(procInfos, procInfo)
 	}
 
+	reporter.Report(scanned, scanned, message)
+
 	// 排序
 	if sortBy == "cpu" {
 		sort.Slice(procInfos, func(i, j int) bool {
@@ -146,68 +186,68 @@ func (pt *ProcessTool) getTopProcesses(sortBy string, limit int) (types.ProcessL
 	return processList, nil
 }
 
-// formatProcessList 格式化进程列表输出
-func (pt *ProcessTool) formatProcessList(processList types.ProcessList, sortBy string, limit int) string {
-	var result string
-
+// buildReport 描述进程列表的输出内容
+func (pt *ProcessTool) buildReport(locale i18n.Locale, processList types.ProcessList, sortBy string, limit int) render.Report {
+	report := render.Report{
+		Locale:  locale,
+		Icon:    "💾",
+		Title:   i18n.T(locale, "process.titleMemory", limit),
+		Summary: []render.Field{{Label: i18n.T(locale, "process.total"), Value: fmt.Sprintf("%d", processList.Total)}},
+		Updated: processList.LastUpdated,
+	}
 	if sortBy == "cpu" {
-		result += fmt.Sprintf("🚀 CPU 占用最高的 %d 个进程\n", limit)
-	} else {
-		result += fmt.Sprintf("💾 内存占用最高的 %d 个进程\n", limit)
+		report.Icon = "🚀"
+		report.Title = i18n.T(locale, "process.titleCPU", limit)
 	}
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-	result += fmt.Sprintf("%-8s %-25s %-10s %-12s %-10s\n", "PID", "进程名", "CPU%", "内存(MB)", "状态")
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
 
+	table := &render.Table{Columns: []string{
+		"PID",
+		i18n.T(locale, "process.name"),
+		"CPU%",
+		i18n.T(locale, "process.memoryMB"),
+		i18n.T(locale, "process.status"),
+	}}
 	for _, proc := range processList.Processes {
-		// 截断过长的进程名
-		name := proc.Name
-		if len(name) > 25 {
-			name = name[:22] + "..."
-		}
-
-		result += fmt.Sprintf("%-8d %-25s %-10.2f %-12.2f %-10s\n",
-			proc.PID,
-			name,
-			proc.CPUPercent,
-			proc.MemoryMB,
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprintf("%d", proc.PID),
+			proc.Name,
+			fmt.Sprintf("%.2f", proc.CPUPercent),
+			fmt.Sprintf("%.2f", proc.MemoryMB),
 			proc.Status,
-		)
+		})
 	}
+	report.Sections = []render.Section{{Table: table}}
 
-	result += fmt.Sprintf("\n📊 总进程数: %d\n", processList.Total)
-	result += fmt.Sprintf("📅 更新时间: %s\n", processList.LastUpdated.Format("2006-01-02 15:04:05"))
-
-	return result
+	return report
 }
 
 // GetProcessData 获取进程数据（供其他组件使用）
-func (pt *ProcessTool) GetProcessData(sortBy string, limit int) (types.ProcessList, error) {
-	return pt.getTopProcesses(sortBy, limit)
+func (pt *ProcessTool) GetProcessData(ctx context.Context, sortBy string, limit int) (types.ProcessList, error) {
+	return pt.getTopProcesses(ctx, sortBy, limit)
 }
 
 // GetProcessByPID 根据 PID 获取特定进程信息
-func (pt *ProcessTool) GetProcessByPID(pid int32) (types.ProcessInfo, error) {
+func (pt *ProcessTool) GetProcessByPID(ctx context.Context, pid int32) (types.ProcessInfo, error) {
 	var procInfo types.ProcessInfo
 
-	p, err := process.NewProcess(pid)
+	p, err := process.NewProcessWithContext(ctx, pid)
 	if err != nil {
 		return procInfo, fmt.Errorf("找不到 PID 为 %d 的进程: %v", pid, err)
 	}
 
-	name, err := p.Name()
+	name, err := p.NameWithContext(ctx)
 	if err != nil {
 		return procInfo, fmt.Errorf("获取进程名失败: %v", err)
 	}
 
-	memInfo, _ := p.MemoryInfo()
-	cpuPercent, _ := p.CPUPercent()
-	statusSlice, _ := p.Status()
+	memInfo, _ := p.MemoryInfoWithContext(ctx)
+	cpuPercent, _ := p.CPUPercentWithContext(ctx)
+	statusSlice, _ := p.StatusWithContext(ctx)
 	status := ""
 	if len(statusSlice) > 0 {
 		status = statusSlice[0]
 	}
-	createTime, _ := p.CreateTime()
+	createTime, _ := p.CreateTimeWithContext(ctx)
 
 	var memBytes uint64
 	var memMB float64
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go-mcp/mcp/types"
+	"runtime/debug"
+	"time"
+)
+
+// progressInterval 为采样类工具汇报进度的间隔
+const progressInterval = time.Second
+
+// sampleWithProgress 执行耗时约 interval 的采样函数，期间定期汇报进度。
+// 进度以秒为单位，从 done 开始累加，total 为整个工具预计的总耗时（秒）。
+// 采样函数在独立的 goroutine 中运行，其中的 panic 无法被调用方 recover，因此转换为错误返回。
+func sampleWithProgress(ctx context.Context, interval time.Duration, done, total float64, message string, sample func() error) error {
+	reporter := types.ProgressReporterFrom(ctx)
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				types.LoggerFrom(ctx).ErrorContext(ctx, "采样时发生 panic", "panic", value, "stack", string(debug.Stack()))
+				result <- fmt.Errorf("panic: %v", value)
+			}
+		}()
+		result <- sample()
+	}()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		select {
+		case err := <-result:
+			if err == nil {
+				reporter.Report(done+interval.Seconds(), total, message)
+			}
+			return err
+		case <-ticker.C:
+			elapsed := min(time.Since(start), interval).Truncate(progressInterval / 10)
+			reporter.Report(done+elapsed.Seconds(), total, message)
+		}
+	}
+}
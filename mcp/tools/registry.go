@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"fmt"
+	"go-mcp/mcp/types"
+	"sort"
+	"sync"
+)
+
+// Registry 保存可供调用的工具，支持在运行时注册、注销、启用与停用。
+// 对客户端可见的工具集合发生变化时，Registry 会依次调用通过 Watch 登记的监听函数。
+// 停用状态按名称记录，可以先停用尚未注册的工具，注册后依然保持停用。
+type Registry struct {
+	mu       sync.RWMutex
+	tools    map[string]types.MonitorTool
+	disabled map[string]bool
+
+	watchers    map[int]func()
+	nextWatcher int
+}
+
+// NewRegistry 创建一个空的工具注册表。
+func NewRegistry() *Registry {
+	return &Registry{
+		tools:    make(map[string]types.MonitorTool),
+		disabled: make(map[string]bool),
+		watchers: make(map[int]func()),
+	}
+}
+
+// Builtins 返回内置的全部监控工具。
+func Builtins() []types.MonitorTool {
+	return []types.MonitorTool{
+		NewCPUTool(),
+		NewDiskTool(),
+		NewMemoryTool(),
+		NewNetworkTool(),
+		NewProcessTool(),
+		NewSystemTool(),
+	}
+}
+
+// RegisterBuiltins 将内置监控工具注册到 r。
+func RegisterBuiltins(r *Registry) error {
+	for _, tool := range Builtins() {
+		if err := r.Register(tool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register 注册一个工具，名称为空或已被占用时返回错误。
+func (r *Registry) Register(tool types.MonitorTool) error {
+	name := tool.GetName()
+	if name == "" {
+		return fmt.Errorf("工具名称不能为空")
+	}
+
+	r.mu.Lock()
+	if _, exists := r.tools[name]; exists {
+		r.mu.Unlock()
+		return fmt.Errorf("工具 %s 已注册", name)
+	}
+	r.tools[name] = tool
+	visible := !r.disabled[name]
+	r.mu.Unlock()
+
+	if visible {
+		r.changed()
+	}
+	return nil
+}
+
+// Unregister 注销指定名称的工具，工具不存在时返回 false。
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	_, exists := r.tools[name]
+	delete(r.tools, name)
+	visible := exists && !r.disabled[name]
+	r.mu.Unlock()
+
+	if visible {
+		r.changed()
+	}
+	return exists
+}
+
+// Enable 重新启用被停用的工具。
+func (r *Registry) Enable(name string) {
+	r.setDisabled(name, false)
+}
+
+// Disable 停用工具：工具保持注册，但不会出现在 tools/list 中，也无法被调用。
+func (r *Registry) Disable(name string) {
+	r.setDisabled(name, true)
+}
+
+// setDisabled 修改工具的停用状态，仅在已注册工具的可见性改变时通知监听者
+func (r *Registry) setDisabled(name string, disabled bool) {
+	r.mu.Lock()
+	changed := r.disabled[name] != disabled
+	if disabled {
+		r.disabled[name] = true
+	} else {
+		delete(r.disabled, name)
+	}
+	_, registered := r.tools[name]
+	r.mu.Unlock()
+
+	if changed && registered {
+		r.changed()
+	}
+}
+
+// Lookup 按名称查找已启用的工具。
+func (r *Registry) Lookup(name string) (types.MonitorTool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tool, exists := r.tools[name]
+	if !exists || r.disabled[name] {
+		return nil, false
+	}
+	return tool, true
+}
+
+// Names 返回全部已启用工具的名称，按字母顺序排列。
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		if !r.disabled[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Watch 登记一个在工具列表变化时调用的函数，返回的函数用于取消登记。
+// 监听函数在修改注册表的 goroutine 中同步调用，不应阻塞。
+func (r *Registry) Watch(fn func()) (cancel func()) {
+	r.mu.Lock()
+	id := r.nextWatcher
+	r.nextWatcher++
+	r.watchers[id] = fn
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		delete(r.watchers, id)
+		r.mu.Unlock()
+	}
+}
+
+// changed 在锁外依次调用监听函数，避免监听函数回调注册表时死锁
+func (r *Registry) changed() {
+	r.mu.RLock()
+	watchers := make([]func(), 0, len(r.watchers))
+	for _, fn := range r.watchers {
+		watchers = append(watchers, fn)
+	}
+	r.mu.RUnlock()
+
+	for _, fn := range watchers {
+		fn()
+	}
+}
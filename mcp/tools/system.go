@@ -1,7 +1,10 @@
 package tools
 
 import (
+	"context"
 	"fmt"
+	"go-mcp/mcp/i18n"
+	"go-mcp/mcp/render"
 	"go-mcp/mcp/types"
 	"time"
 
@@ -12,6 +15,11 @@ import (
 type SystemTool struct {
 }
 
+// systemArguments 为 system_overview 的参数
+type systemArguments struct {
+	IncludeLoad bool `json:"include_load"`
+}
+
 // NewSystemTool 创建新的系统信息工具
 func NewSystemTool() *SystemTool {
 	return &SystemTool{}
@@ -23,48 +31,69 @@ func (st *SystemTool) GetName() string {
 }
 
 // GetDescription 获取工具描述
-func (st *SystemTool) GetDescription() string {
-	return "获取系统综合概览信息"
+func (st *SystemTool) GetDescription(locale i18n.Locale) string {
+	return i18n.T(locale, "system.description")
 }
 
 // GetInputSchema 获取输入模式
-func (st *SystemTool) GetInputSchema() types.InputSchema {
+func (st *SystemTool) GetInputSchema(locale i18n.Locale) types.InputSchema {
 	return types.InputSchema{
 		Type: "object",
 		Properties: map[string]types.Property{
 			"include_load": {
-				Type:        "string",
-				Description: "是否包含系统负载信息",
-				Enum:        []string{"true", "false"},
-				Default:     "true",
+				Type:        "boolean",
+				Description: i18n.T(locale, "system.arg.includeLoad"),
+				Default:     true,
 			},
+			render.FormatArgument: render.FormatProperty(locale),
+			i18n.Argument:         localeProperty(locale),
 		},
+		AdditionalProperties: ptr(false),
 	}
 }
 
+// GetAnnotations 获取工具注解
+func (st *SystemTool) GetAnnotations(locale i18n.Locale) types.ToolAnnotations {
+	return readOnlyAnnotations(i18n.T(locale, "system.toolTitle"))
+}
+
+// GetOutputSchema 获取输出模式，与结构化结果 types.SystemInfo 对应
+func (st *SystemTool) GetOutputSchema() types.OutputSchema {
+	return types.OutputSchemaOf(types.SystemInfo{})
+}
+
 // Execute 执行系统信息获取
-func (st *SystemTool) Execute(args map[string]interface{}) (string, error) {
+func (st *SystemTool) Execute(ctx context.Context, args map[string]interface{}) (types.ToolOutput, error) {
 	// 解析参数
-	includeLoadStr, _ := args["include_load"].(string)
-	includeLoad := includeLoadStr != "false" // 默认为 true
+	locale := i18n.FromContext(ctx)
+	var params systemArguments
+	if err := types.DecodeArguments(st.GetInputSchema(locale), args, &params); err != nil {
+		return types.ToolOutput{}, err
+	}
+	includeLoad := params.IncludeLoad
+
+	format, err := render.FormatFromArgs(locale, args)
+	if err != nil {
+		return types.ToolOutput{}, err
+	}
 
 	// 获取系统信息
-	sysInfo, err := st.getSystemInfo()
+	sysInfo, err := st.getSystemInfo(ctx)
 	if err != nil {
-		return "", fmt.Errorf("获取系统信息失败: %v", err)
+		return types.ToolOutput{}, i18n.Errorf(locale, "system.error.info", err)
 	}
 
-	return st.formatSystemInfo(sysInfo, includeLoad), nil
+	return renderOutput(format, sysInfo, st.buildReport(locale, sysInfo, includeLoad))
 }
 
 // getSystemInfo 获取系统信息
-func (st *SystemTool) getSystemInfo() (types.SystemInfo, error) {
+func (st *SystemTool) getSystemInfo(ctx context.Context) (types.SystemInfo, error) {
 	var sysInfo types.SystemInfo
 
 	// 获取主机信息
-	hostInfo, err := host.Info()
+	hostInfo, err := host.InfoWithContext(ctx)
 	if err != nil {
-		return sysInfo, fmt.Errorf("获取主机信息失败: %v", err)
+		return sysInfo, i18n.Errorf(i18n.FromContext(ctx), "system.error.host", err)
 	}
 
 	// 填充系统信息
@@ -80,49 +109,56 @@ func (st *SystemTool) getSystemInfo() (types.SystemInfo, error) {
 	return sysInfo, nil
 }
 
-// formatSystemInfo 格式化系统信息输出
-func (st *SystemTool) formatSystemInfo(sysInfo types.SystemInfo, includeLoad bool) string {
-	var result string
-
-	result += "🖥️  系统概览\n"
-	result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-	result += fmt.Sprintf("主机名: %s\n", sysInfo.Hostname)
-	result += fmt.Sprintf("操作系统: %s\n", sysInfo.OS)
-	result += fmt.Sprintf("平台: %s\n", sysInfo.Platform)
-	result += fmt.Sprintf("内核版本: %s\n", sysInfo.KernelVersion)
-	result += fmt.Sprintf("架构: %s\n", sysInfo.Architecture)
-
+// buildReport 描述系统信息的输出内容
+func (st *SystemTool) buildReport(locale i18n.Locale, sysInfo types.SystemInfo, includeLoad bool) render.Report {
 	// 格式化运行时间
 	uptime := time.Duration(sysInfo.Uptime) * time.Second
 	days := int(uptime.Hours()) / 24
 	hours := int(uptime.Hours()) % 24
 	minutes := int(uptime.Minutes()) % 60
-	result += fmt.Sprintf("运行时间: %d天 %d小时 %d分钟\n", days, hours, minutes)
 
-	result += fmt.Sprintf("进程数: %d\n", sysInfo.ProcessCount)
+	sections := []render.Section{
+		{
+			Fields: []render.Field{
+				{Label: i18n.T(locale, "system.hostname"), Value: sysInfo.Hostname},
+				{Label: i18n.T(locale, "system.os"), Value: sysInfo.OS},
+				{Label: i18n.T(locale, "system.platform"), Value: sysInfo.Platform},
+				{Label: i18n.T(locale, "system.kernel"), Value: sysInfo.KernelVersion},
+				{Label: i18n.T(locale, "system.arch"), Value: sysInfo.Architecture},
+				{Label: i18n.T(locale, "system.uptime"), Value: i18n.T(locale, "system.uptimeValue", days, hours, minutes)},
+				{Label: i18n.T(locale, "system.processCount"), Value: fmt.Sprintf("%d", sysInfo.ProcessCount)},
+			},
+		},
+	}
 
 	// 包含负载信息 (在某些系统上可能不可用)
 	if includeLoad {
 		// 注意：LoadAvg 在某些系统上可能不可用，这里暂时注释掉
 		// 可以根据需要实现替代方案
-		result += "\n📊 系统负载\n"
-		result += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-		result += "系统负载信息在此平台暂不可用\n"
+		sections = append(sections, render.Section{
+			Icon:  "📊",
+			Title: i18n.T(locale, "system.loadTitle"),
+			Note:  i18n.T(locale, "system.loadUnavailable"),
+		})
 	}
 
-	result += fmt.Sprintf("\n📅 更新时间: %s\n", sysInfo.LastUpdated.Format("2006-01-02 15:04:05"))
-
-	return result
+	return render.Report{
+		Locale:   locale,
+		Icon:     "🖥️",
+		Title:    i18n.T(locale, "system.title"),
+		Sections: sections,
+		Updated:  sysInfo.LastUpdated,
+	}
 }
 
 // GetSystemData 获取系统数据（供其他组件使用）
-func (st *SystemTool) GetSystemData(includeLoad bool) (types.SystemInfo, error) {
-	return st.getSystemInfo()
+func (st *SystemTool) GetSystemData(ctx context.Context, includeLoad bool) (types.SystemInfo, error) {
+	return st.getSystemInfo(ctx)
 }
 
 // GetBootTime 获取系统启动时间
-func (st *SystemTool) GetBootTime() (time.Time, error) {
-	bootTime, err := host.BootTime()
+func (st *SystemTool) GetBootTime(ctx context.Context) (time.Time, error) {
+	bootTime, err := host.BootTimeWithContext(ctx)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("获取系统启动时间失败: %v", err)
 	}
@@ -131,8 +167,8 @@ func (st *SystemTool) GetBootTime() (time.Time, error) {
 }
 
 // GetSystemUsers 获取当前登录的用户
-func (st *SystemTool) GetSystemUsers() ([]map[string]interface{}, error) {
-	users, err := host.Users()
+func (st *SystemTool) GetSystemUsers(ctx context.Context) ([]map[string]interface{}, error) {
+	users, err := host.UsersWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("获取系统用户失败: %v", err)
 	}
@@ -152,8 +188,8 @@ func (st *SystemTool) GetSystemUsers() ([]map[string]interface{}, error) {
 }
 
 // GetSystemTemperature 获取系统温度信息
-func (st *SystemTool) GetSystemTemperature() ([]map[string]interface{}, error) {
-	temps, err := host.SensorsTemperatures()
+func (st *SystemTool) GetSystemTemperature(ctx context.Context) ([]map[string]interface{}, error) {
+	temps, err := host.SensorsTemperaturesWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("获取系统温度失败: %v", err)
 	}
@@ -174,6 +210,7 @@ func (st *SystemTool) GetSystemTemperature() ([]map[string]interface{}, error) {
 
 // GetComprehensiveOverview 获取综合系统概览（包含所有监控数据）
 func (st *SystemTool) GetComprehensiveOverview(
+	ctx context.Context,
 	cpuTool *CPUTool,
 	memTool *MemoryTool,
 	diskTool *DiskTool,
@@ -182,7 +219,7 @@ func (st *SystemTool) GetComprehensiveOverview(
 	var monitorData types.MonitorData
 
 	// 获取系统信息
-	sysInfo, err := st.getSystemInfo()
+	sysInfo, err := st.getSystemInfo(ctx)
 	if err != nil {
 		return monitorData, fmt.Errorf("获取系统信息失败: %v", err)
 	}
@@ -190,7 +227,7 @@ func (st *SystemTool) GetComprehensiveOverview(
 
 	// 获取 CPU 信息
 	if cpuTool != nil {
-		cpuInfo, err := cpuTool.GetCPUData(time.Second)
+		cpuInfo, err := cpuTool.GetCPUData(ctx, time.Second)
 		if err == nil {
 			monitorData.CPU = cpuInfo
 		}
@@ -198,7 +235,7 @@ func (st *SystemTool) GetComprehensiveOverview(
 
 	// 获取内存信息
 	if memTool != nil {
-		memInfo, err := memTool.GetMemoryData()
+		memInfo, err := memTool.GetMemoryData(ctx)
 		if err == nil {
 			monitorData.Memory = memInfo
 		}
@@ -206,7 +243,7 @@ func (st *SystemTool) GetComprehensiveOverview(
 
 	// 获取磁盘信息
 	if diskTool != nil {
-		diskInfo, err := diskTool.GetDiskData(false)
+		diskInfo, err := diskTool.GetDiskData(ctx, false)
 		if err == nil {
 			monitorData.Disk = diskInfo
 		}
@@ -214,7 +251,7 @@ func (st *SystemTool) GetComprehensiveOverview(
 
 	// 获取网络信息
 	if netTool != nil {
-		netInfo, err := netTool.GetNetworkData(false, "")
+		netInfo, err := netTool.GetNetworkData(ctx, false, "")
 		if err == nil {
 			monitorData.Network = netInfo
 		}
@@ -0,0 +1,247 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ArgumentError 描述工具参数不符合输入模式的原因，Field 为参数路径，如 limit 或 filters[0].name。
+type ArgumentError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("invalid argument %s: %s", e.Field, e.Reason)
+}
+
+// Validate 按输入模式校验工具参数，返回第一个不符合的参数对应的 *ArgumentError。
+func (s InputSchema) Validate(args map[string]interface{}) error {
+	for _, name := range s.Required {
+		if _, ok := args[name]; !ok {
+			return &ArgumentError{Field: name, Reason: "required"}
+		}
+	}
+
+	// 按名称顺序校验，保证同样的输入总是报告同一个错误
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return &ArgumentError{Field: name, Reason: "unknown argument"}
+			}
+			continue
+		}
+		if err := property.validate(name, args[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeArguments 校验工具参数并解码到 v 指向的结构体，未提供的参数取模式中的默认值。
+// 结构体字段通过 json 标签与参数名对应。
+func DecodeArguments(schema InputSchema, args map[string]interface{}, v any) error {
+	if err := schema.Validate(args); err != nil {
+		return err
+	}
+
+	merged := make(map[string]interface{}, len(schema.Properties))
+	for name, property := range schema.Properties {
+		if property.Default != nil {
+			merged[name] = property.Default
+		}
+	}
+	for name, value := range args {
+		merged[name] = value
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("编码参数失败: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("解码参数失败: %v", err)
+	}
+	return nil
+}
+
+// validate 校验单个值，path 为值在参数中的位置
+func (p Property) validate(path string, value any) error {
+	value = normalizeNumber(value)
+	if p.Type != "" && !matchesType(p.Type, value) {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("expected %s, got %s", p.Type, jsonType(value))}
+	}
+	if len(p.Enum) > 0 && !slices.ContainsFunc(p.Enum, func(option any) bool { return equalJSON(option, value) }) {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("must be one of %s", formatEnum(p.Enum))}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return p.validateNumber(path, v)
+	case string:
+		return p.validateString(path, v)
+	case []interface{}:
+		return p.validateArray(path, v)
+	case map[string]interface{}:
+		return p.validateObject(path, v)
+	}
+	return nil
+}
+
+// validateNumber 校验数值范围
+func (p Property) validateNumber(path string, v float64) error {
+	if p.Minimum != nil && v < *p.Minimum {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("must be >= %v", *p.Minimum)}
+	}
+	if p.Maximum != nil && v > *p.Maximum {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("must be <= %v", *p.Maximum)}
+	}
+	return nil
+}
+
+// validateString 校验字符串长度与正则
+func (p Property) validateString(path string, v string) error {
+	length := utf8.RuneCountInString(v)
+	if p.MinLength != nil && length < *p.MinLength {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("must be at least %d characters", *p.MinLength)}
+	}
+	if p.MaxLength != nil && length > *p.MaxLength {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("must be at most %d characters", *p.MaxLength)}
+	}
+	if p.Pattern != "" {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return &ArgumentError{Field: path, Reason: fmt.Sprintf("invalid pattern %q in schema: %v", p.Pattern, err)}
+		}
+		if !re.MatchString(v) {
+			return &ArgumentError{Field: path, Reason: fmt.Sprintf("must match pattern %q", p.Pattern)}
+		}
+	}
+	return nil
+}
+
+// validateArray 校验数组长度及每个元素
+func (p Property) validateArray(path string, v []interface{}) error {
+	if p.MinItems != nil && len(v) < *p.MinItems {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("must contain at least %d items", *p.MinItems)}
+	}
+	if p.MaxItems != nil && len(v) > *p.MaxItems {
+		return &ArgumentError{Field: path, Reason: fmt.Sprintf("must contain at most %d items", *p.MaxItems)}
+	}
+	if p.Items == nil {
+		return nil
+	}
+	for i, item := range v {
+		if err := p.Items.validate(path+"["+strconv.Itoa(i)+"]", item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateObject 校验对象的必填字段、已声明字段与其余字段
+func (p Property) validateObject(path string, v map[string]interface{}) error {
+	for _, name := range p.Required {
+		if _, ok := v[name]; !ok {
+			return &ArgumentError{Field: path + "." + name, Reason: "required"}
+		}
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := p.Properties[name]
+		if !ok {
+			if p.AdditionalProperties == nil {
+				continue
+			}
+			property = *p.AdditionalProperties
+		}
+		if err := property.validate(path+"."+name, v[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeNumber 将 Go 代码直接传入的整数等数值转换为 float64，与 JSON 解码的结果一致
+func normalizeNumber(value any) any {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32:
+		return v.Float()
+	}
+	return value
+}
+
+// matchesType 判断 JSON 值是否属于模式声明的类型
+func matchesType(schemaType string, value any) bool {
+	switch schemaType {
+	case "integer":
+		v, ok := value.(float64)
+		return ok && v == math.Trunc(v) && !math.IsInf(v, 0)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonType(value) == schemaType
+	}
+}
+
+// jsonType 返回解码后 JSON 值的类型名称
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equalJSON 按 JSON 语义比较两个值，Go 中以整数声明的枚举值与解码得到的 float64 视为相等
+func equalJSON(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// formatEnum 将枚举值格式化为错误信息中的列表
+func formatEnum(options []any) string {
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Sprint(options)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// testSchema 模拟进程列表工具的输入模式
+func testSchema() InputSchema {
+	minLimit, maxLimit := 1.0, 100.0
+	noExtra := false
+	return InputSchema{
+		Type: "object",
+		Properties: map[string]Property{
+			"sort_by": {Type: "string", Enum: []any{"cpu", "memory"}, Default: "cpu"},
+			"limit":   {Type: "integer", Minimum: &minLimit, Maximum: &maxLimit, Default: 10},
+			"filters": {
+				Type: "array",
+				Items: &Property{
+					Type:       "object",
+					Properties: map[string]Property{"name": {Type: "string"}},
+					Required:   []string{"name"},
+				},
+			},
+			"data": {
+				Type:       "object",
+				Properties: map[string]Property{"field": {Type: "boolean"}},
+			},
+		},
+		Required:             []string{"sort_by"},
+		AdditionalProperties: &noExtra,
+	}
+}
+
+// decodeArgs 按 JSON 解码参数，与从请求中得到的参数一致
+func decodeArgs(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		t.Fatal(err)
+	}
+	return args
+}
+
+func TestValidateArguments(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  string
+		field string
+	}{
+		{"valid", `{"sort_by":"cpu","limit":5}`, ""},
+		{"integral float", `{"sort_by":"cpu","limit":5.0}`, ""},
+		{"missing required", `{"limit":5}`, "sort_by"},
+		{"wrong type", `{"sort_by":"cpu","limit":"5"}`, "limit"},
+		{"float for integer", `{"sort_by":"cpu","limit":1.5}`, "limit"},
+		{"below minimum", `{"sort_by":"cpu","limit":0}`, "limit"},
+		{"enum violation", `{"sort_by":"disk"}`, "sort_by"},
+		{"unknown argument", `{"sort_by":"cpu","verbose":true}`, "verbose"},
+		{"nested field", `{"sort_by":"cpu","data":{"field":"yes"}}`, "data.field"},
+		{"array item", `{"sort_by":"cpu","filters":[{"name":"a"},{"name":1}]}`, "filters[1].name"},
+		{"missing nested required", `{"sort_by":"cpu","filters":[{}]}`, "filters[0].name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testSchema().Validate(decodeArgs(t, tt.args))
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			var argErr *ArgumentError
+			if !errors.As(err, &argErr) {
+				t.Fatalf("Validate = %v, want *ArgumentError", err)
+			}
+			if argErr.Field != tt.field {
+				t.Errorf("field = %q, want %q (%s)", argErr.Field, tt.field, argErr.Reason)
+			}
+		})
+	}
+}
+
+func TestDecodeArguments(t *testing.T) {
+	type params struct {
+		SortBy string `json:"sort_by"`
+		Limit  int    `json:"limit"`
+	}
+
+	tests := []struct {
+		name string
+		args string
+		want params
+	}{
+		{"defaults", `{"sort_by":"memory"}`, params{SortBy: "memory", Limit: 10}},
+		{"explicit", `{"sort_by":"cpu","limit":3}`, params{SortBy: "cpu", Limit: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got params
+			if err := DecodeArguments(testSchema(), decodeArgs(t, tt.args), &got); err != nil {
+				t.Fatalf("DecodeArguments = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("decoded %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// 校验失败时不解码
+	var got params
+	err := DecodeArguments(testSchema(), decodeArgs(t, `{"sort_by":"cpu","limit":1.5}`), &got)
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) || argErr.Field != "limit" {
+		t.Errorf("DecodeArguments = %v, want invalid limit", err)
+	}
+	if got != (params{}) {
+		t.Errorf("decoded %+v after a validation error", got)
+	}
+}
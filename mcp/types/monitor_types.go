@@ -1,6 +1,11 @@
 package types
 
-import "time"
+import (
+	"context"
+	"go-mcp/mcp/i18n"
+	"log/slog"
+	"time"
+)
 
 // 监控数据相关类型定义
 
@@ -132,12 +137,66 @@ type MonitorData struct {
 	Timestamp time.Time   `json:"timestamp"`
 }
 
+// ToolOutput 为工具的执行结果：Data 为与输出模式对应的结构化数据，Text 为面向人阅读的文本。
+type ToolOutput struct {
+	Data any
+	Text string
+}
+
 // 工具接口定义
 type MonitorTool interface {
 	GetName() string
-	GetDescription() string
-	GetInputSchema() InputSchema
-	Execute(args map[string]interface{}) (string, error)
+	GetDescription(locale i18n.Locale) string
+	GetInputSchema(locale i18n.Locale) InputSchema
+	GetOutputSchema() OutputSchema
+	// GetAnnotations 返回工具的行为注解，修改系统状态的工具必须标注 DestructiveHint
+	GetAnnotations(locale i18n.Locale) ToolAnnotations
+	Execute(ctx context.Context, args map[string]interface{}) (ToolOutput, error)
+}
+
+// ProgressReporter 供长时间运行的工具汇报执行进度。
+// progress 需单调递增，total 未知时传 0。
+type ProgressReporter interface {
+	Report(progress, total float64, message string)
+}
+
+type progressReporterKey struct{}
+
+// noopProgressReporter 在客户端未请求进度通知时使用，丢弃所有进度。
+type noopProgressReporter struct{}
+
+func (noopProgressReporter) Report(float64, float64, string) {}
+
+// WithProgressReporter 返回携带进度汇报器的 context，供工具通过 ProgressReporterFrom 取用。
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+// ProgressReporterFrom 取出 context 中的进度汇报器，未设置时返回不做任何事的实现。
+func ProgressReporterFrom(ctx context.Context) ProgressReporter {
+	if reporter, ok := ctx.Value(progressReporterKey{}).(ProgressReporter); ok {
+		return reporter
+	}
+	return noopProgressReporter{}
+}
+
+type loggerKey struct{}
+
+// discardLogger 在未设置日志记录器时使用，丢弃所有日志。
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// WithLogger 返回携带日志记录器的 context，供工具通过 LoggerFrom 取用。
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFrom 取出 context 中的日志记录器，未设置时返回丢弃所有日志的记录器。
+// 工具应通过它记录诊断信息，而不是直接写标准输出（stdio 传输下会破坏 JSON-RPC 消息流）。
+func LoggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return discardLogger
 }
 
 // 数据存储接口
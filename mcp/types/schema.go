@@ -0,0 +1,84 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// OutputSchemaOf 根据结构体的 json 标签生成对应的输出模式，v 必须为结构体（或其指针）。
+// 未标注 omitempty 的字段视为必填字段。
+func OutputSchemaOf(v any) OutputSchema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	object := schemaOf(t)
+	return OutputSchema{
+		Type:       object.Type,
+		Properties: object.Properties,
+		Required:   object.Required,
+	}
+}
+
+// schemaOf 将 Go 类型映射为 JSON Schema
+func schemaOf(t reflect.Type) Property {
+	if t == timeType {
+		return Property{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.Bool:
+		return Property{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Property{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Property{Type: "number"}
+	case reflect.String:
+		return Property{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		items := schemaOf(t.Elem())
+		return Property{Type: "array", Items: &items}
+	case reflect.Map:
+		values := schemaOf(t.Elem())
+		return Property{Type: "object", AdditionalProperties: &values}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		// interface{} 等无法静态确定的类型不做约束
+		return Property{}
+	}
+}
+
+// structSchema 按 encoding/json 的规则展开结构体字段
+func structSchema(t reflect.Type) Property {
+	schema := Property{Type: "object", Properties: make(map[string]Property)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = schemaOf(field.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
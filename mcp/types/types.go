@@ -10,10 +10,13 @@ import (
 const (
 	// JSONRPCVersion is the version of the JSON-RPC protocol we speak.
 	JSONRPCVersion = "2.0"
-	// ProtocolVersion identifies the MCP protocol version supported by this server implementation.
-	ProtocolVersion = "2024-06-24"
+	// ProtocolVersion identifies the latest MCP protocol version supported by this server implementation.
+	ProtocolVersion = "2025-06-18"
 )
 
+// SupportedProtocolVersions lists every MCP protocol version the server can negotiate, newest first.
+var SupportedProtocolVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
 // Request models a JSON-RPC request or notification envelope.
 type Request struct {
 	JSONRPC string          `json:"jsonrpc"`
@@ -30,6 +33,13 @@ type Response struct {
 	Error   *Error          `json:"error,omitempty"`
 }
 
+// Notification models a server-initiated JSON-RPC notification.
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
 // Error represents a JSON-RPC error object.
 type Error struct {
 	Code    int    `json:"code"`
@@ -37,6 +47,23 @@ type Error struct {
 	Data    any    `json:"data,omitempty"`
 }
 
+// ErrorData 为错误对象附带的结构化信息，指出出错的字段、方法、工具或资源。
+type ErrorData struct {
+	Field    string `json:"field,omitempty"`
+	Method   string `json:"method,omitempty"`
+	Tool     string `json:"tool,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+	URI      string `json:"uri,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// WithData 为错误对象附加结构化信息并返回自身，便于链式构造。
+func (e *Error) WithData(data any) *Error {
+	e.Data = data
+	return e
+}
+
 // JSON-RPC error codes used by this implementation.
 const (
 	CodeParseError     = -32700
@@ -51,6 +78,7 @@ const (
 
 // InitializeParams represents the payload for the initialize request defined by MCP.
 type InitializeParams struct {
+	Meta            *RequestMeta       `json:"_meta,omitempty"`
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ClientCapabilities `json:"capabilities"`
 	ClientInfo      ClientInfo         `json:"clientInfo"`
@@ -77,6 +105,8 @@ type InitializeResult struct {
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ServerCapabilities `json:"capabilities"`
 	ServerInfo      ServerInfo         `json:"serverInfo"`
+	// Instructions 描述如何使用本服务器，客户端可以将其加入模型的系统提示
+	Instructions string `json:"instructions,omitempty"`
 }
 
 // ServerCapabilities advertises the MCP capabilities provided by this server.
@@ -84,6 +114,7 @@ type ServerCapabilities struct {
 	Tools     *ToolsCapability     `json:"tools,omitempty"`
 	Resources *ResourcesCapability `json:"resources,omitempty"`
 	Prompts   *PromptsCapability   `json:"prompts,omitempty"`
+	Logging   *LoggingCapability   `json:"logging,omitempty"`
 }
 
 type ToolsCapability struct {
@@ -99,6 +130,9 @@ type PromptsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
+// LoggingCapability 表示服务器支持 logging/setLevel 并通过 notifications/message 推送日志。
+type LoggingCapability struct{}
+
 // ServerInfo announces information about this MCP server.
 type ServerInfo struct {
 	Name    string `json:"name"`
@@ -116,22 +150,101 @@ type PingParams struct {
 	Message string `json:"message,omitempty"`
 }
 
-// PingResult represents the ping response payload.
+// PingResult represents the ping response payload. MCP requires an empty result,
+// so all fields are omitted unless explicitly set.
 type PingResult struct {
-	Status  string `json:"status"`
+	Status  string `json:"status,omitempty"`
 	Message string `json:"message,omitempty"`
 }
 
+// CancelledParams is the payload of notifications/cancelled.
+type CancelledParams struct {
+	RequestID json.RawMessage `json:"requestId"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// RequestMeta carries the optional _meta object of a request.
+// MCP 允许 _meta 携带任意键（如 "com.example/trace"），服务器不认识的键保存在 Extra 中，
+// 不会导致请求被拒绝。
+type RequestMeta struct {
+	ProgressToken json.RawMessage
+	// Locale selects the language of tool descriptions and output, e.g. "en" or "zh-CN".
+	Locale string
+	// Extra 保存 progressToken 与 locale 之外的键及其原始值
+	Extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON 解码 _meta 对象，保留未知的键。locale 不是字符串时视为未提供。
+func (m *RequestMeta) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*m = RequestMeta{}
+	if token, ok := fields["progressToken"]; ok {
+		m.ProgressToken = token
+		delete(fields, "progressToken")
+	}
+	if locale, ok := fields["locale"]; ok {
+		json.Unmarshal(locale, &m.Locale)
+		delete(fields, "locale")
+	}
+	if len(fields) > 0 {
+		m.Extra = fields
+	}
+	return nil
+}
+
+// MarshalJSON 将 _meta 编码为单个对象，Extra 中的键原样输出。
+func (m RequestMeta) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(m.Extra)+2)
+	for key, value := range m.Extra {
+		fields[key] = value
+	}
+	if len(m.ProgressToken) > 0 {
+		fields["progressToken"] = m.ProgressToken
+	}
+	if m.Locale != "" {
+		fields["locale"] = m.Locale
+	}
+	return json.Marshal(fields)
+}
+
+// ProgressParams is the payload of notifications/progress.
+type ProgressParams struct {
+	ProgressToken json.RawMessage `json:"progressToken"`
+	Progress      float64         `json:"progress"`
+	Total         float64         `json:"total,omitempty"`
+	Message       string          `json:"message,omitempty"`
+}
+
+// ListToolsParams is the payload for the tools/list method.
+type ListToolsParams struct {
+	// Cursor 为上一页结果中的 nextCursor，为空时从第一页开始
+	Cursor string       `json:"cursor,omitempty"`
+	Meta   *RequestMeta `json:"_meta,omitempty"`
+}
+
+// ListToolsResult is the payload returned by tools/list.
+type ListToolsResult struct {
+	Tools []ToolDefinition `json:"tools"`
+	// NextCursor 非空表示还有下一页
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
 // CallToolParams is the payload for the tools/call method.
 type CallToolParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Meta      *RequestMeta           `json:"_meta,omitempty"`
 }
 
 // CallToolResult wraps the response returned by a tool invocation.
 type CallToolResult struct {
-	Content []ContentItem `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	Content           []ContentItem `json:"content"`
+	StructuredContent any           `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
 }
 
 // ContentItem is a minimal text-based MCP content payload.
@@ -140,6 +253,44 @@ type ContentItem struct {
 	Text string `json:"text"`
 }
 
+// Prompt 描述一个可通过 prompts/get 获取的提示模板。
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument 描述提示模板接受的参数。
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ListPromptsResult is the payload returned by prompts/list.
+type ListPromptsResult struct {
+	Prompts []Prompt `json:"prompts"`
+}
+
+// GetPromptParams is the payload for the prompts/get method.
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+	Meta      *RequestMeta      `json:"_meta,omitempty"`
+}
+
+// GetPromptResult wraps the rendered messages of a prompt.
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptMessage 是提示模板中的一条消息。
+type PromptMessage struct {
+	Role    string      `json:"role"`
+	Content ContentItem `json:"content"`
+}
+
 // CloneID 拷贝 JSON-RPC 请求或响应的 ID，避免共享底层切片。
 func CloneID(id *json.RawMessage) *json.RawMessage {
 	if id == nil {
@@ -150,14 +301,13 @@ func CloneID(id *json.RawMessage) *json.RawMessage {
 	return &clone
 }
 
-// DecodeParams 将 JSON-RPC 参数解码为目标结构，启用严格字段校验。
+// DecodeParams 将 JSON-RPC 参数解码为目标结构。MCP 的请求参数允许携带扩展字段，
+// 因此忽略未知字段，只校验已知字段的类型；工具参数的严格校验由 InputSchema.Validate 负责。
 func DecodeParams(raw json.RawMessage, target any) error {
-	if len(raw) == 0 {
+	if len(raw) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
 		return nil
 	}
-	decoder := json.NewDecoder(bytes.NewReader(raw))
-	decoder.DisallowUnknownFields()
-	return decoder.Decode(target)
+	return json.Unmarshal(raw, target)
 }
 
 // NewError 构造通用的 JSON-RPC 错误对象。
@@ -187,32 +337,178 @@ func NewToolError(err error) *Error {
 }
 
 type ToolDefinition struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description,omitempty"`
-	InputSchema InputSchema `json:"inputSchema"`
+	Name         string           `json:"name"`
+	Title        string           `json:"title,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	InputSchema  InputSchema      `json:"inputSchema"`
+	OutputSchema *OutputSchema    `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations 描述工具行为的提示信息，客户端据此决定是否需要用户确认。
+// 这些只是提示，客户端不应把来自不可信服务器的注解当作安全保证。
+type ToolAnnotations struct {
+	// Title 为面向用户的工具名称
+	Title string `json:"title,omitempty"`
+	// ReadOnlyHint 为 true 表示工具不会修改环境
+	ReadOnlyHint *bool `json:"readOnlyHint,omitempty"`
+	// DestructiveHint 为 true 表示工具可能执行破坏性修改，仅在 ReadOnlyHint 为 false 时有意义
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+	// IdempotentHint 为 true 表示以相同参数重复调用不会产生额外影响，仅在 ReadOnlyHint 为 false 时有意义
+	IdempotentHint *bool `json:"idempotentHint,omitempty"`
+	// OpenWorldHint 为 true 表示工具会与外部实体交互，false 表示只作用于封闭的本地环境
+	OpenWorldHint *bool `json:"openWorldHint,omitempty"`
 }
 
 type InputSchema struct {
 	Type       string              `json:"type"`
 	Properties map[string]Property `json:"properties,omitempty"`
 	Required   []string            `json:"required,omitempty"`
+	// AdditionalProperties 为 false 时拒绝 Properties 之外的参数
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
 }
 
+// Property 描述一个参数或字段的 JSON Schema，Type 取值为 string、integer、number、boolean、array、object 或 null，
+// 为空时不限制类型。
 type Property struct {
-	Type        string   `json:"type"`
-	Description string   `json:"description,omitempty"`
-	Enum        []string `json:"enum,omitempty"`
-	Default     string   `json:"default,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Default     any    `json:"default,omitempty"`
+
+	// 数值约束
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	// 字符串约束，Pattern 为 RE2 语法的正则表达式
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	Format    string `json:"format,omitempty"`
+
+	// 数组约束
+	Items    *Property `json:"items,omitempty"`
+	MinItems *int      `json:"minItems,omitempty"`
+	MaxItems *int      `json:"maxItems,omitempty"`
+
+	// 对象约束，AdditionalProperties 描述 Properties 之外的键对应的值
+	Properties           map[string]Property `json:"properties,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	AdditionalProperties *Property           `json:"additionalProperties,omitempty"`
+}
+
+// OutputSchema 描述工具 structuredContent 的 JSON Schema，顶层类型必须为 object。
+type OutputSchema struct {
+	Type       string              `json:"type"`
+	Properties map[string]Property `json:"properties,omitempty"`
+	Required   []string            `json:"required,omitempty"`
+}
+
+// Resource 描述一个可通过 resources/read 读取的 MCP 资源。
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceTemplate 描述一类参数化资源，URI 模板遵循 RFC 6570。
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ListResourcesResult is the payload returned by resources/list.
+type ListResourcesResult struct {
+	Resources []Resource `json:"resources"`
+}
+
+// ListResourceTemplatesResult is the payload returned by resources/templates/list.
+type ListResourceTemplatesResult struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// ReadResourceParams is the payload for the resources/read method.
+type ReadResourceParams struct {
+	URI  string       `json:"uri"`
+	Meta *RequestMeta `json:"_meta,omitempty"`
+}
+
+// ReadResourceResult wraps the contents returned by resources/read.
+type ReadResourceResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// SubscribeParams is the payload for resources/subscribe and resources/unsubscribe.
+type SubscribeParams struct {
+	URI  string       `json:"uri"`
+	Meta *RequestMeta `json:"_meta,omitempty"`
+}
+
+// ResourceUpdatedParams is the payload of notifications/resources/updated.
+type ResourceUpdatedParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourceContents 是资源的文本内容。
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// LoggingLevel 为 MCP 日志级别，取值与 RFC 5424 的 syslog 严重级别对应。
+type LoggingLevel string
+
+// MCP 日志级别，按严重程度递增
+const (
+	LoggingLevelDebug     LoggingLevel = "debug"
+	LoggingLevelInfo      LoggingLevel = "info"
+	LoggingLevelNotice    LoggingLevel = "notice"
+	LoggingLevelWarning   LoggingLevel = "warning"
+	LoggingLevelError     LoggingLevel = "error"
+	LoggingLevelCritical  LoggingLevel = "critical"
+	LoggingLevelAlert     LoggingLevel = "alert"
+	LoggingLevelEmergency LoggingLevel = "emergency"
+)
+
+// SetLevelParams is the payload for the logging/setLevel method.
+type SetLevelParams struct {
+	Level LoggingLevel `json:"level"`
+	Meta  *RequestMeta `json:"_meta,omitempty"`
+}
+
+// LoggingMessageParams is the payload of notifications/message.
+type LoggingMessageParams struct {
+	Level  LoggingLevel `json:"level"`
+	Logger string       `json:"logger,omitempty"`
+	Data   any          `json:"data"`
 }
 
 // MCP 方法常量
 const (
+	MethodPing                    = "ping"
 	MethodInitialize              = "initialize"
 	MethodInitialized             = "initialized"
 	MethodNotificationInitialized = "notifications/initialized"
 	MethodListTools               = "tools/list"
 	MethodCallTool                = "tools/call"
 	MethodListPrompts             = "prompts/list"
+	MethodGetPrompt               = "prompts/get"
 	MethodListResources           = "resources/list"
 	MethodReadResource            = "resources/read"
+	MethodListResourceTemplates   = "resources/templates/list"
+	MethodSubscribeResource       = "resources/subscribe"
+	MethodUnsubscribeResource     = "resources/unsubscribe"
+	MethodSetLevel                = "logging/setLevel"
+
+	MethodNotificationResourceUpdated  = "notifications/resources/updated"
+	MethodNotificationToolsChanged     = "notifications/tools/list_changed"
+	MethodNotificationResourcesChanged = "notifications/resources/list_changed"
+	MethodNotificationPromptsChanged   = "notifications/prompts/list_changed"
+	MethodNotificationCancelled        = "notifications/cancelled"
+	MethodNotificationProgress         = "notifications/progress"
+	MethodNotificationMessage          = "notifications/message"
 )